@@ -101,3 +101,44 @@ func (c *Client) CreateMcpClient(mcpClient *McpClient) (string, error) {
 
 	return response.AccessToken, nil
 }
+
+// UpdateMcpClientInput is the input structure for updating an MCP client.
+// Only the fields that are set (non-nil) are changed.
+type UpdateMcpClientInput struct {
+	Description *string `json:"description,omitempty"`
+	// AllowList replaces the list of MCP servers that the client is allowed to access
+	AllowList *[]string `json:"allow_list,omitempty"`
+}
+
+// UpdateMcpClient changes the description and/or the allow list of an MCP client.
+func (c *Client) UpdateMcpClient(name string, input *UpdateMcpClientInput) (*McpClient, error) {
+	u, _ := c.constructAPIEndpoint("/clients/" + name)
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal client data: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPatch, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var mcpClient McpClient
+	if err := json.NewDecoder(resp.Body).Decode(&mcpClient); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &mcpClient, nil
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// PromptArgument describes an argument accepted by a prompt template.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Prompt represents a prompt provided by an MCP Server registered in the registry.
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// ListPrompts fetches the list of prompts, optionally filtered by server name.
+func (c *Client) ListPrompts(server string) ([]*Prompt, error) {
+	u, _ := c.constructAPIEndpoint("/prompts")
+	req, _ := c.newRequest(http.MethodGet, u, nil)
+	if server != "" {
+		q := req.URL.Query()
+		q.Add("server", server)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var prompts []*Prompt
+	if err := json.NewDecoder(resp.Body).Decode(&prompts); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return prompts, nil
+}
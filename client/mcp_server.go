@@ -6,27 +6,131 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
 // Server represents an MCP server registered in the MCPJungle registry.
 type Server struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Transport   string `json:"transport"`
 	URL         string `json:"url"`
+	AuthType    string `json:"auth_type,omitempty"`
+
+	// Version and Instructions are reported by the MCP server itself.
+	Version      string `json:"version,omitempty"`
+	Instructions string `json:"instructions,omitempty"`
+
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+
+	ConnectTimeout int `json:"connect_timeout,omitempty"`
+	CallTimeout    int `json:"call_timeout,omitempty"`
+	MaxRetries     int `json:"max_retries,omitempty"`
+
+	// Process is only present for stdio servers and describes the state of the server's process.
+	Process *ServerProcess `json:"process,omitempty"`
+
+	// CircuitBreaker describes the circuit breaker guarding calls to the server.
+	CircuitBreaker *ServerCircuitBreaker `json:"circuit_breaker,omitempty"`
+
+	// Health is the outcome of the registry's latest background health check of the server.
+	Health *ServerHealth `json:"health,omitempty"`
+}
+
+// ServerHealth describes the health of an MCP server as seen by the registry's background health checks.
+type ServerHealth struct {
+	// Status is one of "unknown", "healthy" or "unhealthy".
+	Status      string     `json:"status"`
+	LastChecked *time.Time `json:"last_checked,omitempty"`
+	LastSeen    *time.Time `json:"last_seen,omitempty"`
+	LatencyMs   int64      `json:"latency_ms"`
+	LastError   string     `json:"last_error,omitempty"`
+}
+
+// ServerCircuitBreaker describes the circuit breaker guarding calls to an MCP server.
+// While the breaker is open, calls to the server fail fast without reaching it.
+type ServerCircuitBreaker struct {
+	State     string     `json:"state"`
+	Failures  int        `json:"failures"`
+	OpenedAt  *time.Time `json:"opened_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// ServerProcess describes the process of a stdio MCP server supervised by MCPJungle.
+type ServerProcess struct {
+	State     string     `json:"state"`
+	PID       int        `json:"pid,omitempty"`
+	Restarts  int        `json:"restarts"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
 }
 
 // RegisterServerInput is the input structure for registering a new MCP server.
 type RegisterServerInput struct {
-	Name        string `json:"name"`
+	// Name is optional. If empty, the registry names the server after the name the server reports about itself.
+	Name string `json:"name,omitempty"`
+	// Description is optional. If empty, the registry uses the first line of the instructions of the server.
 	Description string `json:"description"`
 
-	// URL is mandatory and must be a valid http/https URL (eg- https://example.com/mcp).
-	// MCPJungle only supports streamable HTTP transport as of now.
-	URL string `json:"url"`
+	// Transport is the transport used to communicate with the MCP server.
+	// Valid values are "streamable_http", "sse" and "stdio".
+	// If empty, the registry detects whether the server at URL supports streamable HTTP or SSE.
+	Transport string `json:"transport,omitempty"`
+
+	// URL is mandatory for HTTP-based servers and must be a valid http/https URL (eg- https://example.com/mcp).
+	URL string `json:"url,omitempty"`
 
 	// BearerToken is an optional token used for authenticating requests to the MCP server.
 	// It is useful when the upstream MCP server requires static tokens (e.g., API tokens) for authentication.
 	BearerToken string `json:"bearer_token,omitempty"`
+
+	// AuthType is the scheme used to authenticate with an HTTP-based MCP server.
+	// Valid values are "bearer", "basic", "header" and "oauth2".
+	// If empty, BearerToken is sent as a bearer token if present.
+	AuthType string `json:"auth_type,omitempty"`
+
+	// BasicAuthUsername and BasicAuthPassword are used by the "basic" auth type.
+	BasicAuthUsername string `json:"basic_auth_username,omitempty"`
+	BasicAuthPassword string `json:"basic_auth_password,omitempty"`
+
+	// AuthHeaderName and AuthHeaderValue are used by the "header" auth type (eg- X-API-Key: <key>).
+	AuthHeaderName  string `json:"auth_header_name,omitempty"`
+	AuthHeaderValue string `json:"auth_header_value,omitempty"`
+
+	// OAuthTokenURL, OAuthClientID, OAuthClientSecret and OAuthScopes are used by the "oauth2" auth type,
+	// which obtains access tokens using the OAuth 2.0 client credentials grant.
+	// OAuthScopes is a space-separated list of scopes.
+	OAuthTokenURL     string `json:"oauth_token_url,omitempty"`
+	OAuthClientID     string `json:"oauth_client_id,omitempty"`
+	OAuthClientSecret string `json:"oauth_client_secret,omitempty"`
+	OAuthScopes       string `json:"oauth_scopes,omitempty"`
+
+	// TLSClientCert and TLSClientKey are the PEM-encoded client certificate and private key for mutual TLS.
+	// TLSCACert is a PEM-encoded CA bundle used to verify the MCP server's certificate instead of the system roots.
+	// TLSServerName overrides the name used to verify the MCP server's certificate.
+	TLSClientCert string `json:"tls_client_cert,omitempty"`
+	TLSClientKey  string `json:"tls_client_key,omitempty"`
+	TLSCACert     string `json:"tls_ca_cert,omitempty"`
+	TLSServerName string `json:"tls_server_name,omitempty"`
+
+	// Headers contains additional HTTP headers to send in all requests to the MCP server.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// ConnectTimeout and CallTimeout are the maximum number of seconds allowed for establishing a session
+	// with the MCP server and for a single call to it. Zero selects the registry's defaults.
+	ConnectTimeout int `json:"connect_timeout,omitempty"`
+	CallTimeout    int `json:"call_timeout,omitempty"`
+
+	// MaxRetries is the number of times a call to a read-only tool is retried if the MCP server cannot be reached.
+	MaxRetries int `json:"max_retries,omitempty"`
+
+	// Command, Args, Env and WorkDir describe the process MCPJungle runs for a stdio MCP server.
+	// Command is mandatory for stdio servers.
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	WorkDir string            `json:"work_dir,omitempty"`
 }
 
 // RegisterServer registers a new MCP server with the registry.
@@ -61,6 +165,79 @@ func (c *Client) RegisterServer(server *RegisterServerInput) (*Server, error) {
 	return &registeredServer, nil
 }
 
+// UpdateServerInput is the input structure for updating a registered MCP server.
+// Only the fields that are set (non-nil) are changed, all other settings of the server keep their current values.
+// The fields have the same meaning as in RegisterServerInput. Setting a field to its zero value clears it.
+type UpdateServerInput struct {
+	Description *string `json:"description,omitempty"`
+	Transport   *string `json:"transport,omitempty"`
+	URL         *string `json:"url,omitempty"`
+
+	BearerToken       *string `json:"bearer_token,omitempty"`
+	AuthType          *string `json:"auth_type,omitempty"`
+	BasicAuthUsername *string `json:"basic_auth_username,omitempty"`
+	BasicAuthPassword *string `json:"basic_auth_password,omitempty"`
+	AuthHeaderName    *string `json:"auth_header_name,omitempty"`
+	AuthHeaderValue   *string `json:"auth_header_value,omitempty"`
+
+	OAuthTokenURL     *string `json:"oauth_token_url,omitempty"`
+	OAuthClientID     *string `json:"oauth_client_id,omitempty"`
+	OAuthClientSecret *string `json:"oauth_client_secret,omitempty"`
+	OAuthScopes       *string `json:"oauth_scopes,omitempty"`
+
+	TLSClientCert *string `json:"tls_client_cert,omitempty"`
+	TLSClientKey  *string `json:"tls_client_key,omitempty"`
+	TLSCACert     *string `json:"tls_ca_cert,omitempty"`
+	TLSServerName *string `json:"tls_server_name,omitempty"`
+
+	// Headers replaces all the additional HTTP headers of the server.
+	Headers *map[string]string `json:"headers,omitempty"`
+
+	ConnectTimeout *int `json:"connect_timeout,omitempty"`
+	CallTimeout    *int `json:"call_timeout,omitempty"`
+	MaxRetries     *int `json:"max_retries,omitempty"`
+
+	// Args and Env replace all the arguments and environment variables of a stdio server's command.
+	Command *string            `json:"command,omitempty"`
+	Args    *[]string          `json:"args,omitempty"`
+	Env     *map[string]string `json:"env,omitempty"`
+	WorkDir *string            `json:"work_dir,omitempty"`
+}
+
+// UpdateServer changes the settings of a registered MCP server.
+// The registry validates the new settings by connecting to the server before applying them,
+// and refreshes the server's tools along with them.
+func (c *Client) UpdateServer(name string, input *UpdateServerInput) (*Server, error) {
+	u, _ := c.constructAPIEndpoint("/servers/" + name)
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize server data into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPatch, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var updatedServer Server
+	if err := json.NewDecoder(resp.Body).Decode(&updatedServer); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &updatedServer, nil
+}
+
 // ListServers fetches the list of registered servers.
 func (c *Client) ListServers() ([]*Server, error) {
 	u, _ := c.constructAPIEndpoint("/servers")
@@ -87,6 +264,66 @@ func (c *Client) ListServers() ([]*Server, error) {
 	return servers, nil
 }
 
+// GetServerHealth fetches the latest health status of a registered MCP server.
+func (c *Client) GetServerHealth(name string) (*ServerHealth, error) {
+	u, _ := c.constructAPIEndpoint("/servers/" + name + "/health")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var health ServerHealth
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &health, nil
+}
+
+// ServerSyncResult describes how the tools of an MCP server changed when the registry re-fetched them.
+// All tool names are canonical, ie- prefixed with the server name.
+type ServerSyncResult struct {
+	Added   []string `json:"added"`
+	Updated []string `json:"updated"`
+	Removed []string `json:"removed"`
+}
+
+// SyncServer asks the registry to re-fetch the tools of an MCP server and returns the changes it found.
+func (c *Client) SyncServer(name string) (*ServerSyncResult, error) {
+	u, _ := c.constructAPIEndpoint("/servers/" + name + "/sync")
+	req, err := c.newRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var result ServerSyncResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
 // DeregisterServer deletes a server by name.
 func (c *Client) DeregisterServer(name string) error {
 	u, _ := c.constructAPIEndpoint("/servers/" + name)
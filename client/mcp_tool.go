@@ -15,17 +15,46 @@ type ToolInputSchema struct {
 	Required   []string       `json:"required,omitempty"`
 }
 
+// ToolAnnotations contains the hints provided by the MCP server about a tool's behavior
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+// ToolOverrides contains the parts of a tool's definition that were changed by an admin
+type ToolOverrides struct {
+	Alias                 string            `json:"alias,omitempty"`
+	Title                 string            `json:"title,omitempty"`
+	Description           string            `json:"description,omitempty"`
+	ParameterDescriptions map[string]string `json:"parameter_descriptions,omitempty"`
+}
+
 // Tool represents a tool provided by an MCP Server registered in the registry.
+// Its definition already includes its overrides.
 type Tool struct {
-	Name        string          `json:"name"`
-	Description string          `json:"description"`
-	InputSchema ToolInputSchema `json:"input_schema"`
+	Name         string           `json:"name"`
+	Title        string           `json:"title,omitempty"`
+	Description  string           `json:"description"`
+	InputSchema  ToolInputSchema  `json:"input_schema"`
+	OutputSchema map[string]any   `json:"output_schema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+	Overrides    ToolOverrides    `json:"overrides"`
+	// Enabled is false if the tool was disabled, in which case MCP clients can't see or call it
+	Enabled bool `json:"enabled"`
 }
 
 type ToolInvokeResult struct {
 	Meta    map[string]any   `json:"_meta,omitempty"`
 	IsError bool             `json:"isError,omitempty"`
 	Content []map[string]any `json:"content"`
+	// StructuredContent is the result of the tool as a JSON object, if the tool provides one
+	StructuredContent map[string]any `json:"structuredContent,omitempty"`
+	// Warning is set when the result doesn't conform to the tool's output schema.
+	// The result is returned as the tool provided it regardless.
+	Warning string `json:"warning,omitempty"`
 }
 
 // ListTools fetches the list of tools, optionally filtered by server name.
@@ -120,3 +149,85 @@ func (c *Client) InvokeTool(name string, input map[string]any) (*ToolInvokeResul
 
 	return result, nil
 }
+
+// UpdateToolInput is the input structure for changing the overrides of a tool.
+// Only the fields that are set (non-nil) are changed, empty values remove the override.
+type UpdateToolInput struct {
+	Alias       *string `json:"alias,omitempty"`
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	// ParameterDescriptions contains the new descriptions of some of the tool's input parameters
+	ParameterDescriptions map[string]string `json:"parameter_descriptions,omitempty"`
+}
+
+// UpdateTool changes the overrides of a tool and returns its new definition.
+func (c *Client) UpdateTool(name string, input *UpdateToolInput) (*Tool, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tool data: %w", err)
+	}
+
+	u, _ := c.constructAPIEndpoint("/tool")
+	req, err := c.newRequest(http.MethodPatch, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	q := req.URL.Query()
+	q.Add("name", name)
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var tool Tool
+	if err := json.NewDecoder(resp.Body).Decode(&tool); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &tool, nil
+}
+
+// EnableTool makes a disabled tool available to MCP clients again.
+func (c *Client) EnableTool(name string) (*Tool, error) {
+	return c.setToolEnabled(name, "/tools/enable")
+}
+
+// DisableTool hides a tool from MCP clients and rejects its calls.
+func (c *Client) DisableTool(name string) (*Tool, error) {
+	return c.setToolEnabled(name, "/tools/disable")
+}
+
+func (c *Client) setToolEnabled(name, path string) (*Tool, error) {
+	body, _ := json.Marshal(map[string]string{"name": name})
+	u, _ := c.constructAPIEndpoint(path)
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var tool Tool
+	if err := json.NewDecoder(resp.Body).Decode(&tool); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &tool, nil
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Toolset is a named selection of tools, served as its own MCP server at `/mcp/<name>`.
+type Toolset struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Instructions string `json:"instructions"`
+
+	// Tools contains the entries selecting the tools of the toolset, eg- `github/git_commit` or `github/*`
+	Tools []string `json:"tools"`
+}
+
+// CreateToolset creates a new toolset.
+func (c *Client) CreateToolset(toolset *Toolset) error {
+	u, _ := c.constructAPIEndpoint("/toolsets")
+
+	body, err := json.Marshal(toolset)
+	if err != nil {
+		return fmt.Errorf("failed to marshal toolset data: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// ListToolsets fetches the list of toolsets.
+func (c *Client) ListToolsets() ([]Toolset, error) {
+	u, _ := c.constructAPIEndpoint("/toolsets")
+
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var toolsets []Toolset
+	if err := json.NewDecoder(resp.Body).Decode(&toolsets); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return toolsets, nil
+}
+
+// DeleteToolset deletes a toolset.
+func (c *Client) DeleteToolset(name string) error {
+	u, _ := c.constructAPIEndpoint("/toolsets/" + name)
+
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
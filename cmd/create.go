@@ -29,6 +29,24 @@ var (
 	createMcpClientCmdDescription    string
 )
 
+var createToolsetCmd = &cobra.Command{
+	Use:   "toolset [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Create a toolset served as its own MCP server",
+	Long: "Create a toolset, a named selection of tools from the registry.\n" +
+		"The toolset is served as its own MCP server at `/mcp/<name>`, which only exposes the selected tools.\n" +
+		"Tools are selected by their canonical names (eg- github/git_commit) or by patterns in which `*` matches " +
+		"any characters (eg- github/*). A server name alone selects all the tools of that server.",
+	Example: "  mcpjungle create toolset coding --tools \"github/*,filesystem/read_file\" --instructions \"Use these tools to work on code\"",
+	RunE:    runCreateToolset,
+}
+
+var (
+	createToolsetCmdTools        string
+	createToolsetCmdDescription  string
+	createToolsetCmdInstructions string
+)
+
 func init() {
 	createMcpClientCmd.Flags().StringVar(
 		&createMcpClientCmdAllowedServers,
@@ -44,19 +62,45 @@ func init() {
 		"Description of the MCP client. This is optional and can be used to provide additional context.",
 	)
 
+	createToolsetCmd.Flags().StringVar(
+		&createToolsetCmdTools,
+		"tools",
+		"",
+		"Comma-separated list of the tools (or patterns) selected by the toolset",
+	)
+	createToolsetCmd.Flags().StringVar(
+		&createToolsetCmdDescription,
+		"description",
+		"",
+		"Description of the toolset",
+	)
+	createToolsetCmd.Flags().StringVar(
+		&createToolsetCmdInstructions,
+		"instructions",
+		"",
+		"Instructions sent to MCP clients when they connect to the toolset's MCP server",
+	)
+	_ = createToolsetCmd.MarkFlagRequired("tools")
+
 	createCmd.AddCommand(createMcpClientCmd)
+	createCmd.AddCommand(createToolsetCmd)
 	rootCmd.AddCommand(createCmd)
 }
 
-func runCreateMcpClient(cmd *cobra.Command, args []string) error {
-	// convert the comma-separated list of allowed servers into a slice
+// parseAllowList converts a comma-separated list (eg- of allowed servers) into a slice.
+func parseAllowList(s string) []string {
 	allowList := make([]string, 0)
-	for _, s := range strings.Split(createMcpClientCmdAllowedServers, ",") {
+	for _, s := range strings.Split(s, ",") {
 		trimmed := strings.TrimSpace(s)
 		if trimmed != "" {
 			allowList = append(allowList, trimmed)
 		}
 	}
+	return allowList
+}
+
+func runCreateMcpClient(cmd *cobra.Command, args []string) error {
+	allowList := parseAllowList(createMcpClientCmdAllowedServers)
 
 	c := &client.McpClient{
 		Name:        args[0],
@@ -85,3 +129,18 @@ func runCreateMcpClient(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runCreateToolset(cmd *cobra.Command, args []string) error {
+	t := &client.Toolset{
+		Name:         args[0],
+		Description:  createToolsetCmdDescription,
+		Instructions: createToolsetCmdInstructions,
+		Tools:        parseAllowList(createToolsetCmdTools),
+	}
+	if err := apiClient.CreateToolset(t); err != nil {
+		return fmt.Errorf("failed to create toolset: %w", err)
+	}
+	fmt.Printf("Toolset '%s' created successfully!\n", t.Name)
+	fmt.Printf("Its MCP server is available at /mcp/%s\n", t.Name)
+	return nil
+}
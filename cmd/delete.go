@@ -19,8 +19,17 @@ var deleteMcpClientCmd = &cobra.Command{
 	RunE: runDeleteMcpClient,
 }
 
+var deleteToolsetCmd = &cobra.Command{
+	Use:   "toolset [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a toolset",
+	Long:  "Delete a toolset. Its MCP server stops being available immediately.",
+	RunE:  runDeleteToolset,
+}
+
 func init() {
 	deleteCmd.AddCommand(deleteMcpClientCmd)
+	deleteCmd.AddCommand(deleteToolsetCmd)
 	rootCmd.AddCommand(deleteCmd)
 }
 
@@ -32,3 +41,12 @@ func runDeleteMcpClient(cmd *cobra.Command, args []string) error {
 	fmt.Printf("MCP client '%s' deleted successfully (if it existed)!\n", name)
 	return nil
 }
+
+func runDeleteToolset(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeleteToolset(name); err != nil {
+		return fmt.Errorf("failed to delete the toolset: %w", err)
+	}
+	fmt.Printf("Toolset '%s' deleted successfully (if it existed)!\n", name)
+	return nil
+}
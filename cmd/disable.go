@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var disableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disable resources",
+}
+
+var disableToolCmd = &cobra.Command{
+	Use:   "tool <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Disable a tool",
+	Long: "Disable a tool without deregistering the MCP server that provides it.\n" +
+		"The tool disappears from the MCP proxy and its calls are rejected, until it is enabled again.\n" +
+		"The tool stays disabled when the server's tools are synced.",
+	Example: "  mcpjungle disable tool github/delete_repo",
+	RunE:    runDisableTool,
+}
+
+func init() {
+	disableCmd.AddCommand(disableToolCmd)
+	rootCmd.AddCommand(disableCmd)
+}
+
+func runDisableTool(cmd *cobra.Command, args []string) error {
+	t, err := apiClient.DisableTool(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to disable tool: %w", err)
+	}
+	fmt.Printf("Tool %s disabled successfully!\n", t.Name)
+	return nil
+}
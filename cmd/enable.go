@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var enableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enable resources",
+}
+
+var enableToolCmd = &cobra.Command{
+	Use:   "tool <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Enable a disabled tool",
+	Long:  "Make a disabled tool available to MCP clients again.",
+	RunE:  runEnableTool,
+}
+
+func init() {
+	enableCmd.AddCommand(enableToolCmd)
+	rootCmd.AddCommand(enableCmd)
+}
+
+func runEnableTool(cmd *cobra.Command, args []string) error {
+	t, err := apiClient.EnableTool(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to enable tool: %w", err)
+	}
+	fmt.Printf("Tool %s enabled successfully!\n", t.Name)
+	return nil
+}
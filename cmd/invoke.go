@@ -154,5 +154,21 @@ func runInvokeTool(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if result.StructuredContent != nil {
+		fmt.Println()
+		fmt.Println("[Structured content]")
+		j, err := json.MarshalIndent(result.StructuredContent, "", "  ")
+		if err != nil {
+			// Simply print the raw object if we fail to marshal it
+			fmt.Println(result.StructuredContent)
+		} else {
+			fmt.Println(string(j))
+		}
+	}
+	if result.Warning != "" {
+		fmt.Println()
+		fmt.Println("Warning:", result.Warning)
+	}
+
 	return nil
 }
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/mcpjungle/mcpjungle/client"
 	"github.com/spf13/cobra"
 	"strings"
+	"time"
 )
 
 var listCmd = &cobra.Command{
@@ -20,6 +22,15 @@ var listToolsCmd = &cobra.Command{
 	RunE:  runListTools,
 }
 
+var listPromptsCmdServerName string
+
+var listPromptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: "List available prompts",
+	Long:  "List prompts available either from a specific MCP server or across all MCP servers registered in the registry.",
+	RunE:  runListPrompts,
+}
+
 var listServersCmd = &cobra.Command{
 	Use:   "servers",
 	Short: "List registered MCP servers",
@@ -34,6 +45,12 @@ var listMcpClientsCmd = &cobra.Command{
 	RunE: runListMcpClients,
 }
 
+var listToolsetsCmd = &cobra.Command{
+	Use:   "toolsets",
+	Short: "List toolsets",
+	RunE:  runListToolsets,
+}
+
 func init() {
 	listToolsCmd.Flags().StringVar(
 		&listToolsCmdServerName,
@@ -42,9 +59,18 @@ func init() {
 		"Filter tools by server name",
 	)
 
+	listPromptsCmd.Flags().StringVar(
+		&listPromptsCmdServerName,
+		"server",
+		"",
+		"Filter prompts by server name",
+	)
+
 	listCmd.AddCommand(listToolsCmd)
+	listCmd.AddCommand(listPromptsCmd)
 	listCmd.AddCommand(listServersCmd)
 	listCmd.AddCommand(listMcpClientsCmd)
+	listCmd.AddCommand(listToolsetsCmd)
 
 	rootCmd.AddCommand(listCmd)
 }
@@ -60,7 +86,11 @@ func runListTools(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 	for i, t := range tools {
-		fmt.Printf("%d. %s\n", i+1, t.Name)
+		fmt.Printf("%d. %s", i+1, t.Name)
+		if !t.Enabled {
+			fmt.Print(" (disabled)")
+		}
+		fmt.Println()
 		fmt.Println(t.Description)
 		fmt.Println()
 	}
@@ -70,6 +100,39 @@ func runListTools(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runListPrompts(cmd *cobra.Command, args []string) error {
+	prompts, err := apiClient.ListPrompts(listPromptsCmdServerName)
+	if err != nil {
+		return fmt.Errorf("failed to list prompts: %w", err)
+	}
+
+	if len(prompts) == 0 {
+		fmt.Println("There are no prompts in the registry")
+		return nil
+	}
+	for i, p := range prompts {
+		fmt.Printf("%d. %s\n", i+1, p.Name)
+		if p.Description != "" {
+			fmt.Println(p.Description)
+		}
+		if len(p.Arguments) > 0 {
+			args := make([]string, len(p.Arguments))
+			for j, a := range p.Arguments {
+				args[j] = a.Name
+				if a.Required {
+					args[j] += " (required)"
+				}
+			}
+			fmt.Println("Arguments: " + strings.Join(args, ", "))
+		}
+		if i < len(prompts)-1 {
+			fmt.Println()
+		}
+	}
+
+	return nil
+}
+
 func runListServers(cmd *cobra.Command, args []string) error {
 	servers, err := apiClient.ListServers()
 	if err != nil {
@@ -81,9 +144,36 @@ func runListServers(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 	for i, s := range servers {
-		fmt.Printf("%d. %s\n", i+1, s.Name)
-		fmt.Println(s.URL)
+		fmt.Printf("%d. %s", i+1, s.Name)
+		if s.Version != "" {
+			fmt.Printf(" (version %s)", s.Version)
+		}
+		fmt.Println()
+		if s.Transport == "stdio" {
+			fmt.Println(strings.Join(append([]string{s.Command}, s.Args...), " "))
+		} else {
+			fmt.Println(s.URL)
+		}
 		fmt.Println(s.Description)
+		if s.Health != nil {
+			printServerHealth(s.Health)
+		}
+		if s.Process != nil {
+			fmt.Printf("Process: %s", s.Process.State)
+			if s.Process.PID != 0 {
+				fmt.Printf(" (pid %d)", s.Process.PID)
+			}
+			fmt.Printf(", restarts: %d\n", s.Process.Restarts)
+			if s.Process.LastError != "" {
+				fmt.Println("Last error:", s.Process.LastError)
+			}
+		}
+		if s.CircuitBreaker != nil && s.CircuitBreaker.State != "closed" {
+			fmt.Printf("Circuit breaker: %s after %d consecutive failures\n", s.CircuitBreaker.State, s.CircuitBreaker.Failures)
+			if s.CircuitBreaker.LastError != "" {
+				fmt.Println("Last error:", s.CircuitBreaker.LastError)
+			}
+		}
 		if i < len(servers)-1 {
 			fmt.Println()
 		}
@@ -92,6 +182,20 @@ func runListServers(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func printServerHealth(h *client.ServerHealth) {
+	fmt.Printf("Health: %s", h.Status)
+	if h.Status == "healthy" {
+		fmt.Printf(" (latency %dms)", h.LatencyMs)
+	}
+	if h.LastSeen != nil {
+		fmt.Printf(", last seen %s ago", time.Since(*h.LastSeen).Round(time.Second))
+	}
+	fmt.Println()
+	if h.LastError != "" {
+		fmt.Println("Health check error:", h.LastError)
+	}
+}
+
 func runListMcpClients(cmd *cobra.Command, args []string) error {
 	clients, err := apiClient.ListMcpClients()
 	if err != nil {
@@ -122,3 +226,27 @@ func runListMcpClients(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runListToolsets(cmd *cobra.Command, args []string) error {
+	toolsets, err := apiClient.ListToolsets()
+	if err != nil {
+		return fmt.Errorf("failed to list toolsets: %w", err)
+	}
+
+	if len(toolsets) == 0 {
+		fmt.Println("There are no toolsets in the registry")
+		return nil
+	}
+	for i, t := range toolsets {
+		fmt.Printf("%d. %s (/mcp/%s)\n", i+1, t.Name, t.Name)
+		if t.Description != "" {
+			fmt.Println(t.Description)
+		}
+		fmt.Println("Tools: " + strings.Join(t.Tools, ","))
+		if i < len(toolsets)-1 {
+			fmt.Println()
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/mcpjungle/mcpjungle/client"
 	"github.com/spf13/cobra"
+	"os"
+	"strings"
 )
 
 var (
@@ -11,13 +13,49 @@ var (
 	registerCmdServerURL   string
 	registerCmdServerDesc  string
 	registerCmdBearerToken string
+
+	registerCmdAuthType          string
+	registerCmdBasicAuthUsername string
+	registerCmdBasicAuthPassword string
+	registerCmdAuthHeaderName    string
+	registerCmdAuthHeaderValue   string
+	registerCmdHeaders           []string
+
+	registerCmdOAuthTokenURL     string
+	registerCmdOAuthClientID     string
+	registerCmdOAuthClientSecret string
+	registerCmdOAuthScopes       []string
+
+	registerCmdTLSCertFile   string
+	registerCmdTLSKeyFile    string
+	registerCmdTLSCAFile     string
+	registerCmdTLSServerName string
+
+	registerCmdConnectTimeout int
+	registerCmdCallTimeout    int
+	registerCmdMaxRetries     int
+
+	registerCmdTransport string
+	registerCmdCommand   string
+	registerCmdArgs      []string
+	registerCmdEnv       []string
+	registerCmdWorkDir   string
 )
 
 var registerMCPServerCmd = &cobra.Command{
 	Use:   "register",
 	Short: "Register an MCP Server",
-	Long:  "Register a MCP Server with the registry.\nA server name is unique across the registry and must not contain a slash '/'",
-	RunE:  runRegisterMCPServer,
+	Long: "Register a MCP Server with the registry.\n" +
+		"A server name is unique across the registry and must not contain a slash '/' " +
+		"(or the tool name separator the registry is configured with).\n" +
+		"If no --name is given, the server is named after the name it reports about itself " +
+		"(with a numeric suffix if that name is already taken).\n\n" +
+		"Servers using the streamable HTTP or SSE transport require a --url.\n" +
+		"For servers using the stdio transport, MCPJungle runs the --command itself and keeps it alive.",
+	Example: "  mcpjungle register --name calculator --url http://127.0.0.1:8000/mcp\n" +
+		"  mcpjungle register --name filesystem --transport stdio --command npx " +
+		"--arg -y --arg @modelcontextprotocol/server-filesystem --arg /tmp",
+	RunE: runRegisterMCPServer,
 }
 
 func init() {
@@ -25,7 +63,7 @@ func init() {
 		&registerCmdServerName,
 		"name",
 		"",
-		"MCP server name",
+		"MCP server name. If not specified, the name reported by the MCP server itself is used.",
 	)
 	registerMCPServerCmd.Flags().StringVar(
 		&registerCmdServerURL,
@@ -46,21 +84,245 @@ func init() {
 		"If provided, MCPJungle will use this token to authenticate with the MCP server for all requests."+
 			" This is useful if the MCP server requires static tokens (eg- your API token) for authentication.",
 	)
-
-	// TODO: name should not be mandatory.
-	//  If not supplied, name should be read from MCP server metadata by the registry.
-	_ = registerMCPServerCmd.MarkFlagRequired("name")
-	_ = registerMCPServerCmd.MarkFlagRequired("url")
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdAuthType,
+		"auth-type",
+		"",
+		"Scheme used to authenticate with the MCP server ('bearer' | 'basic' | 'header' | 'oauth2')."+
+			" Defaults to 'bearer' if --bearer-token is provided.",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdBasicAuthUsername,
+		"basic-auth-username",
+		"",
+		"Username for the 'basic' auth type",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdBasicAuthPassword,
+		"basic-auth-password",
+		"",
+		"Password for the 'basic' auth type",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdAuthHeaderName,
+		"auth-header-name",
+		"",
+		"Name of the header that carries the secret for the 'header' auth type (eg- X-API-Key)",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdAuthHeaderValue,
+		"auth-header-value",
+		"",
+		"Secret sent in the --auth-header-name header for the 'header' auth type",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdOAuthTokenURL,
+		"oauth-token-url",
+		"",
+		"Token endpoint used to obtain access tokens with the client credentials grant for the 'oauth2' auth type",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdOAuthClientID,
+		"oauth-client-id",
+		"",
+		"Client ID for the 'oauth2' auth type",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdOAuthClientSecret,
+		"oauth-client-secret",
+		"",
+		"Client secret for the 'oauth2' auth type",
+	)
+	registerMCPServerCmd.Flags().StringArrayVar(
+		&registerCmdOAuthScopes,
+		"oauth-scope",
+		nil,
+		"Scope to request for the 'oauth2' auth type. Repeat the flag to request multiple scopes.",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdTLSCertFile,
+		"tls-cert-file",
+		"",
+		"Path to a PEM-encoded client certificate that MCPJungle presents to the MCP server for mutual TLS",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdTLSKeyFile,
+		"tls-key-file",
+		"",
+		"Path to the PEM-encoded private key of the --tls-cert-file certificate",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdTLSCAFile,
+		"tls-ca-file",
+		"",
+		"Path to a PEM-encoded CA bundle used to verify the MCP server's certificate instead of the system roots",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdTLSServerName,
+		"tls-server-name",
+		"",
+		"Server name used to verify the MCP server's certificate, if it differs from the host in --url",
+	)
+	registerMCPServerCmd.Flags().IntVar(
+		&registerCmdConnectTimeout,
+		"connect-timeout",
+		0,
+		"Maximum number of seconds to wait for a session to be established with the MCP server (default 30)",
+	)
+	registerMCPServerCmd.Flags().IntVar(
+		&registerCmdCallTimeout,
+		"call-timeout",
+		0,
+		"Maximum number of seconds a single tool call to the MCP server may take (default 300)",
+	)
+	registerMCPServerCmd.Flags().IntVar(
+		&registerCmdMaxRetries,
+		"max-retries",
+		0,
+		"Number of times a call to a read-only tool is retried if the MCP server cannot be reached."+
+			" Calls to other tools are never retried.",
+	)
+	registerMCPServerCmd.Flags().StringArrayVar(
+		&registerCmdHeaders,
+		"header",
+		nil,
+		"Additional HTTP header to send to the MCP server in 'Name: value' format."+
+			" Repeat the flag to send multiple headers.",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdTransport,
+		"transport",
+		"",
+		"Transport used to communicate with the MCP server ('streamable_http' | 'sse' | 'stdio')."+
+			" If not specified, the registry detects whether a server at --url supports streamable_http or sse.",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdCommand,
+		"command",
+		"",
+		"Command that runs the MCP server (stdio transport only, eg- npx, uvx, /path/to/server)",
+	)
+	registerMCPServerCmd.Flags().StringArrayVar(
+		&registerCmdArgs,
+		"arg",
+		nil,
+		"Argument to pass to the command (stdio transport only). Repeat the flag to pass multiple arguments.",
+	)
+	registerMCPServerCmd.Flags().StringArrayVar(
+		&registerCmdEnv,
+		"env",
+		nil,
+		"Environment variable to set for the command in KEY=VALUE format (stdio transport only)."+
+			" Repeat the flag to set multiple variables.",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdWorkDir,
+		"workdir",
+		"",
+		"Working directory of the command (stdio transport only)",
+	)
 
 	rootCmd.AddCommand(registerMCPServerCmd)
 }
 
+// parseEnvFlags converts a list of KEY=VALUE pairs into a map.
+func parseEnvFlags(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	env := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid environment variable '%s', expected KEY=VALUE", p)
+		}
+		env[k] = v
+	}
+	return env, nil
+}
+
+// parseHeaderFlags converts a list of 'Name: value' pairs into a map.
+func parseHeaderFlags(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	headers := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, ":")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid header '%s', expected 'Name: value'", p)
+		}
+		headers[k] = strings.TrimSpace(v)
+	}
+	return headers, nil
+}
+
+// readOptionalFile returns the contents of the file at path, or an empty string if path is empty.
+func readOptionalFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return string(b), nil
+}
+
 func runRegisterMCPServer(cmd *cobra.Command, args []string) error {
+	env, err := parseEnvFlags(registerCmdEnv)
+	if err != nil {
+		return err
+	}
+	headers, err := parseHeaderFlags(registerCmdHeaders)
+	if err != nil {
+		return err
+	}
+	tlsCert, err := readOptionalFile(registerCmdTLSCertFile)
+	if err != nil {
+		return err
+	}
+	tlsKey, err := readOptionalFile(registerCmdTLSKeyFile)
+	if err != nil {
+		return err
+	}
+	tlsCA, err := readOptionalFile(registerCmdTLSCAFile)
+	if err != nil {
+		return err
+	}
+
 	input := &client.RegisterServerInput{
 		Name:        registerCmdServerName,
 		URL:         registerCmdServerURL,
 		Description: registerCmdServerDesc,
 		BearerToken: registerCmdBearerToken,
+		Transport:   registerCmdTransport,
+		Command:     registerCmdCommand,
+		Args:        registerCmdArgs,
+		Env:         env,
+		WorkDir:     registerCmdWorkDir,
+
+		AuthType:          registerCmdAuthType,
+		BasicAuthUsername: registerCmdBasicAuthUsername,
+		BasicAuthPassword: registerCmdBasicAuthPassword,
+		AuthHeaderName:    registerCmdAuthHeaderName,
+		AuthHeaderValue:   registerCmdAuthHeaderValue,
+		Headers:           headers,
+
+		OAuthTokenURL:     registerCmdOAuthTokenURL,
+		OAuthClientID:     registerCmdOAuthClientID,
+		OAuthClientSecret: registerCmdOAuthClientSecret,
+		OAuthScopes:       strings.Join(registerCmdOAuthScopes, " "),
+
+		TLSClientCert: tlsCert,
+		TLSClientKey:  tlsKey,
+		TLSCACert:     tlsCA,
+		TLSServerName: registerCmdTLSServerName,
+
+		ConnectTimeout: registerCmdConnectTimeout,
+		CallTimeout:    registerCmdCallTimeout,
+		MaxRetries:     registerCmdMaxRetries,
 	}
 	s, err := apiClient.RegisterServer(input)
 	if err != nil {
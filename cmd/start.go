@@ -14,7 +14,10 @@ import (
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
 	"github.com/spf13/cobra"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 )
 
 const (
@@ -24,11 +27,19 @@ const (
 	DBUrlEnvVar = "DATABASE_URL"
 
 	ServerModeEnvVar = "SERVER_MODE"
+
+	AllowStdioEnvVar = "ALLOW_STDIO"
+
+	ToolNameSeparatorEnvVar = "TOOL_NAME_SEPARATOR"
+	ToolNameMaxLengthEnvVar = "TOOL_NAME_MAX_LENGTH"
 )
 
 var (
-	startServerCmdBindPort    string
-	startServerCmdProdEnabled bool
+	startServerCmdBindPort          string
+	startServerCmdProdEnabled       bool
+	startServerCmdAllowStdio        bool
+	startServerCmdToolNameSeparator string
+	startServerCmdToolNameMaxLength int
 )
 
 var startServerCmd = &cobra.Command{
@@ -57,9 +68,64 @@ func init() {
 		),
 	)
 
+	startServerCmd.Flags().BoolVar(
+		&startServerCmdAllowStdio,
+		"allow-stdio",
+		false,
+		fmt.Sprintf(
+			"Allow registering MCP servers that use the stdio transport, whose commands run on this host."+
+				" Only enable this if everyone who can reach the registry's API is trusted."+
+				" Alternatively, set the %s environment variable to 'true'",
+			AllowStdioEnvVar,
+		),
+	)
+	startServerCmd.Flags().StringVar(
+		&startServerCmdToolNameSeparator,
+		"tool-name-separator",
+		"",
+		fmt.Sprintf(
+			"separator between the server name and the tool name in tool names, default '%s' (overrides env var %s)."+
+				" Use '__' if your LLM provider doesn't accept slashes in function names",
+			mcp.DefaultToolNameSeparator, ToolNameSeparatorEnvVar,
+		),
+	)
+	startServerCmd.Flags().IntVar(
+		&startServerCmdToolNameMaxLength,
+		"tool-name-max-length",
+		0,
+		fmt.Sprintf(
+			"maximum length of tool names, longer names are truncated and end with a hash."+
+				" 0 means unlimited (overrides env var %s)",
+			ToolNameMaxLengthEnvVar,
+		),
+	)
+
 	rootCmd.AddCommand(startServerCmd)
 }
 
+// configureToolNames sets the format of the names tools are exposed with, from the flags or the environment.
+func configureToolNames() error {
+	separator := startServerCmdToolNameSeparator
+	if separator == "" {
+		separator = os.Getenv(ToolNameSeparatorEnvVar)
+	}
+	if separator == "" {
+		separator = mcp.DefaultToolNameSeparator
+	}
+
+	maxLength := startServerCmdToolNameMaxLength
+	if maxLength == 0 {
+		if v := os.Getenv(ToolNameMaxLengthEnvVar); v != "" {
+			var err error
+			if maxLength, err = strconv.Atoi(v); err != nil {
+				return fmt.Errorf("invalid value for %s environment variable: '%s', a number is expected", ToolNameMaxLengthEnvVar, v)
+			}
+		}
+	}
+
+	return mcp.ConfigureToolNames(separator, maxLength)
+}
+
 func runStartServer(cmd *cobra.Command, args []string) error {
 	_ = godotenv.Load()
 
@@ -85,17 +151,31 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 		port = BindPortDefault
 	}
 
+	if err := configureToolNames(); err != nil {
+		return err
+	}
+
 	// create the MCP proxy server
+	// the hooks are populated by the MCP service once it is created
+	proxyHooks := &server.Hooks{}
 	mcpProxyServer := server.NewMCPServer(
 		"MCPJungle Proxy MCP Server",
 		"0.0.1",
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, true),
+		server.WithPromptCapabilities(true),
+		server.WithLogging(),
+		server.WithHooks(proxyHooks),
 	)
 
 	mcpService, err := mcp.NewMCPService(dbConn, mcpProxyServer)
 	if err != nil {
 		return fmt.Errorf("failed to create MCP service: %v", err)
 	}
+	mcpService.RegisterProxyHooks(proxyHooks)
+	if startServerCmdAllowStdio || strings.EqualFold(os.Getenv(AllowStdioEnvVar), "true") {
+		mcpService.AllowStdioServers()
+	}
 
 	mcpClientService := mcp_client.NewMCPClientService(dbConn)
 
@@ -172,9 +252,24 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// run the server in the background so that the processes of stdio MCP servers
+	// can be shut down when MCPJungle is asked to exit.
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Start()
+	}()
 	fmt.Printf("MCPJungle HTTP server listening on :%s\n\n", port)
-	if err := s.Start(); err != nil {
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-errCh:
+		mcpService.Shutdown()
 		return fmt.Errorf("failed to run the server: %v\n", err)
+	case <-sigCh:
+		fmt.Println("Shutting down MCPJungle server...")
+		mcpService.Shutdown()
 	}
 
 	return nil
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var syncMCPServerCmd = &cobra.Command{
+	Use:   "sync <server>",
+	Short: "Re-fetch the tools of an MCP server",
+	Long: "Re-fetch the tools provided by a registered MCP server and update the registry accordingly.\n" +
+		"New tools are registered, changed tools are updated and tools the server no longer provides are " +
+		"deregistered. Tools that didn't change remain available to MCP clients throughout.",
+	Args: cobra.ExactArgs(1),
+	RunE: runSyncMCPServer,
+}
+
+func init() {
+	rootCmd.AddCommand(syncMCPServerCmd)
+}
+
+func runSyncMCPServer(cmd *cobra.Command, args []string) error {
+	server := args[0]
+	result, err := apiClient.SyncServer(server)
+	if err != nil {
+		return fmt.Errorf("failed to sync MCP server %s: %w", server, err)
+	}
+
+	if len(result.Added)+len(result.Updated)+len(result.Removed) == 0 {
+		fmt.Printf("The tools of MCP server %s are already up to date\n", server)
+		return nil
+	}
+	fmt.Printf("Synced the tools of MCP server %s:\n", server)
+	for _, name := range result.Added {
+		fmt.Println("+ " + name + " (added)")
+	}
+	for _, name := range result.Updated {
+		fmt.Println("~ " + name + " (updated)")
+	}
+	for _, name := range result.Removed {
+		fmt.Println("- " + name + " (removed)")
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update resources",
+}
+
+var updateServerCmd = &cobra.Command{
+	Use:   "server <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Update a registered MCP server",
+	Long: "Change the settings of a registered MCP server without deregistering it.\n" +
+		"Only the settings passed as flags are changed, all others keep their current values.\n" +
+		"The registry connects to the server with the new settings and refreshes its tools before applying them, " +
+		"so the server's tools remain available to MCP clients throughout.",
+	Example: "  mcpjungle update server calculator --url http://127.0.0.1:9000/mcp\n" +
+		"  mcpjungle update server github --bearer-token <new-token>",
+	RunE: runUpdateServer,
+}
+
+// updateServerFlags are the flags of the register command that are also accepted by update server.
+// They are copied from the register command rather than redefined, so both always accept the same settings.
+// Each command gets its own flag objects, only the variables the values are parsed into are shared.
+var updateServerFlags = []string{
+	"description", "transport", "url",
+	"bearer-token", "auth-type", "basic-auth-username", "basic-auth-password", "auth-header-name", "auth-header-value",
+	"oauth-token-url", "oauth-client-id", "oauth-client-secret", "oauth-scope",
+	"tls-cert-file", "tls-key-file", "tls-ca-file", "tls-server-name",
+	"header",
+	"connect-timeout", "call-timeout", "max-retries",
+	"command", "arg", "env", "workdir",
+}
+
+var updateMcpClientCmd = &cobra.Command{
+	Use:   "mcp-client <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Update an MCP client (Production mode)",
+	Long: "Change the description or the MCP servers that an MCP client is allowed to access.\n" +
+		"Only the settings passed as flags are changed.\n" +
+		"The new allow list applies immediately, and the client's open sessions are told to refresh their tools.\n" +
+		"This command is only available in Production mode.",
+	Example: "  mcpjungle update mcp-client cursor-local --allow calculator,github\n" +
+		"  mcpjungle update mcp-client cursor-local --allow \"\"",
+	RunE: runUpdateMcpClient,
+}
+
+var (
+	updateMcpClientCmdAllowedServers string
+	updateMcpClientCmdDescription    string
+)
+
+var updateToolCmd = &cobra.Command{
+	Use:   "tool <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Change how a tool is exposed",
+	Long: "Override the name, title, description or parameter descriptions of a tool without changing " +
+		"the MCP server that provides it.\n" +
+		"Only the settings passed as flags are changed. Pass an empty value to remove an override.\n" +
+		"An alias replaces the tool's name (after the server name), the tool is still called by its own name " +
+		"on the MCP server. Overrides are kept when the tool is synced.",
+	Example: "  mcpjungle update tool github/search --alias search_issues --description \"Search GitHub issues\"\n" +
+		"  mcpjungle update tool github/search_issues --param-description \"q=Search terms, eg- is:open label:bug\"\n" +
+		"  mcpjungle update tool github/search_issues --alias \"\"",
+	RunE: runUpdateTool,
+}
+
+var (
+	updateToolCmdAlias             string
+	updateToolCmdTitle             string
+	updateToolCmdDescription       string
+	updateToolCmdParamDescriptions []string
+)
+
+func init() {
+	for _, name := range updateServerFlags {
+		f := *registerMCPServerCmd.Flags().Lookup(name)
+		updateServerCmd.Flags().AddFlag(&f)
+	}
+
+	updateMcpClientCmd.Flags().StringVar(
+		&updateMcpClientCmdAllowedServers,
+		"allow",
+		"",
+		"Comma-separated list of MCP servers that this client is allowed to access.\n"+
+			"It replaces the current list. Pass an empty string to revoke access to all MCP servers.",
+	)
+	updateMcpClientCmd.Flags().StringVar(
+		&updateMcpClientCmdDescription,
+		"description",
+		"",
+		"New description of the MCP client",
+	)
+
+	updateToolCmd.Flags().StringVar(&updateToolCmdAlias, "alias", "", "New name of the tool, without the server name")
+	updateToolCmd.Flags().StringVar(&updateToolCmdTitle, "title", "", "New title of the tool")
+	updateToolCmd.Flags().StringVar(&updateToolCmdDescription, "description", "", "New description of the tool")
+	updateToolCmd.Flags().StringArrayVar(
+		&updateToolCmdParamDescriptions,
+		"param-description",
+		nil,
+		"New description of an input parameter of the tool in the form NAME=DESCRIPTION (can be repeated)",
+	)
+
+	updateCmd.AddCommand(updateServerCmd)
+	updateCmd.AddCommand(updateMcpClientCmd)
+	updateCmd.AddCommand(updateToolCmd)
+	rootCmd.AddCommand(updateCmd)
+}
+
+func runUpdateServer(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	input := &client.UpdateServerInput{}
+
+	stringFlags := map[string]struct {
+		dst **string
+		val string
+	}{
+		"description":         {&input.Description, registerCmdServerDesc},
+		"transport":           {&input.Transport, registerCmdTransport},
+		"url":                 {&input.URL, registerCmdServerURL},
+		"bearer-token":        {&input.BearerToken, registerCmdBearerToken},
+		"auth-type":           {&input.AuthType, registerCmdAuthType},
+		"basic-auth-username": {&input.BasicAuthUsername, registerCmdBasicAuthUsername},
+		"basic-auth-password": {&input.BasicAuthPassword, registerCmdBasicAuthPassword},
+		"auth-header-name":    {&input.AuthHeaderName, registerCmdAuthHeaderName},
+		"auth-header-value":   {&input.AuthHeaderValue, registerCmdAuthHeaderValue},
+		"oauth-token-url":     {&input.OAuthTokenURL, registerCmdOAuthTokenURL},
+		"oauth-client-id":     {&input.OAuthClientID, registerCmdOAuthClientID},
+		"oauth-client-secret": {&input.OAuthClientSecret, registerCmdOAuthClientSecret},
+		"oauth-scope":         {&input.OAuthScopes, strings.Join(registerCmdOAuthScopes, " ")},
+		"tls-server-name":     {&input.TLSServerName, registerCmdTLSServerName},
+		"command":             {&input.Command, registerCmdCommand},
+		"workdir":             {&input.WorkDir, registerCmdWorkDir},
+	}
+	for name, f := range stringFlags {
+		if flags.Changed(name) {
+			v := f.val
+			*f.dst = &v
+		}
+	}
+
+	fileFlags := map[string]struct {
+		dst  **string
+		path string
+	}{
+		"tls-cert-file": {&input.TLSClientCert, registerCmdTLSCertFile},
+		"tls-key-file":  {&input.TLSClientKey, registerCmdTLSKeyFile},
+		"tls-ca-file":   {&input.TLSCACert, registerCmdTLSCAFile},
+	}
+	for name, f := range fileFlags {
+		if flags.Changed(name) {
+			contents, err := readOptionalFile(f.path)
+			if err != nil {
+				return err
+			}
+			*f.dst = &contents
+		}
+	}
+
+	intFlags := map[string]struct {
+		dst **int
+		val int
+	}{
+		"connect-timeout": {&input.ConnectTimeout, registerCmdConnectTimeout},
+		"call-timeout":    {&input.CallTimeout, registerCmdCallTimeout},
+		"max-retries":     {&input.MaxRetries, registerCmdMaxRetries},
+	}
+	for name, f := range intFlags {
+		if flags.Changed(name) {
+			v := f.val
+			*f.dst = &v
+		}
+	}
+
+	if flags.Changed("header") {
+		headers, err := parseHeaderFlags(registerCmdHeaders)
+		if err != nil {
+			return err
+		}
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		input.Headers = &headers
+	}
+	if flags.Changed("arg") {
+		a := registerCmdArgs
+		if a == nil {
+			a = []string{}
+		}
+		input.Args = &a
+	}
+	if flags.Changed("env") {
+		env, err := parseEnvFlags(registerCmdEnv)
+		if err != nil {
+			return err
+		}
+		if env == nil {
+			env = map[string]string{}
+		}
+		input.Env = &env
+	}
+
+	s, err := apiClient.UpdateServer(args[0], input)
+	if err != nil {
+		return fmt.Errorf("failed to update server: %w", err)
+	}
+	fmt.Printf("Server %s updated successfully!\n", s.Name)
+	return nil
+}
+
+func runUpdateMcpClient(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	input := &client.UpdateMcpClientInput{}
+	if flags.Changed("description") {
+		input.Description = &updateMcpClientCmdDescription
+	}
+	if flags.Changed("allow") {
+		allowList := parseAllowList(updateMcpClientCmdAllowedServers)
+		input.AllowList = &allowList
+	}
+	if input.Description == nil && input.AllowList == nil {
+		return fmt.Errorf("nothing to update, pass --allow and/or --description")
+	}
+
+	c, err := apiClient.UpdateMcpClient(args[0], input)
+	if err != nil {
+		return fmt.Errorf("failed to update MCP client: %w", err)
+	}
+	fmt.Printf("MCP client '%s' updated successfully!\n", c.Name)
+	if len(c.AllowList) > 0 {
+		fmt.Println("Servers accessible: " + strings.Join(c.AllowList, ","))
+	} else {
+		fmt.Println("This client does not have access to any MCP servers.")
+	}
+	return nil
+}
+
+func runUpdateTool(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	input := &client.UpdateToolInput{}
+	if flags.Changed("alias") {
+		input.Alias = &updateToolCmdAlias
+	}
+	if flags.Changed("title") {
+		input.Title = &updateToolCmdTitle
+	}
+	if flags.Changed("description") {
+		input.Description = &updateToolCmdDescription
+	}
+	if flags.Changed("param-description") {
+		descriptions, err := parseParamDescriptionFlags(updateToolCmdParamDescriptions)
+		if err != nil {
+			return err
+		}
+		input.ParameterDescriptions = descriptions
+	}
+	if input.Alias == nil && input.Title == nil && input.Description == nil && input.ParameterDescriptions == nil {
+		return fmt.Errorf("nothing to update, pass --alias, --title, --description and/or --param-description")
+	}
+
+	t, err := apiClient.UpdateTool(args[0], input)
+	if err != nil {
+		return fmt.Errorf("failed to update tool: %w", err)
+	}
+	fmt.Printf("Tool %s updated successfully!\n", args[0])
+	if t.Name != args[0] {
+		fmt.Printf("It is now exposed as %s\n", t.Name)
+	}
+	return nil
+}
+
+// parseParamDescriptionFlags converts a list of NAME=DESCRIPTION pairs into a map.
+// An empty description is kept, it removes the override of the parameter.
+func parseParamDescriptionFlags(pairs []string) (map[string]string, error) {
+	descriptions := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		name, description, ok := strings.Cut(p, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --param-description '%s', expected NAME=DESCRIPTION", p)
+		}
+		descriptions[name] = description
+	}
+	return descriptions, nil
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/mcpjungle/mcpjungle/client"
 	"github.com/spf13/cobra"
 	"slices"
 	"strings"
@@ -26,7 +27,27 @@ func runGetToolUsage(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Println(t.Name)
+	if !t.Enabled {
+		fmt.Println("This tool is disabled, MCP clients can't see or call it.")
+	}
+	if title := toolTitle(t); title != "" {
+		fmt.Println(title)
+	}
 	fmt.Println(t.Description)
+	if hints := toolHints(t.Annotations); len(hints) > 0 {
+		fmt.Println("Behavior: " + strings.Join(hints, ", "))
+	}
+
+	if t.OutputSchema != nil {
+		fmt.Println()
+		fmt.Println("Output Schema:")
+		j, err := json.MarshalIndent(t.OutputSchema, "", "  ")
+		if err != nil {
+			fmt.Println(t.OutputSchema)
+		} else {
+			fmt.Println(string(j))
+		}
+	}
 
 	if len(t.InputSchema.Properties) == 0 {
 		fmt.Println("This tool does not require any input parameters.")
@@ -60,3 +81,37 @@ func runGetToolUsage(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// toolTitle returns the human-readable title of the tool, if the MCP server provides one.
+func toolTitle(t *client.Tool) string {
+	if t.Title != "" {
+		return t.Title
+	}
+	if t.Annotations != nil {
+		return t.Annotations.Title
+	}
+	return ""
+}
+
+// toolHints describes the behavior hints that the MCP server has set on a tool.
+func toolHints(a *client.ToolAnnotations) []string {
+	if a == nil {
+		return nil
+	}
+	var hints []string
+	describe := func(hint *bool, yes, no string) {
+		if hint == nil {
+			return
+		}
+		if *hint {
+			hints = append(hints, yes)
+		} else {
+			hints = append(hints, no)
+		}
+	}
+	describe(a.ReadOnlyHint, "read-only", "modifies its environment")
+	describe(a.DestructiveHint, "destructive", "non-destructive")
+	describe(a.IdempotentHint, "idempotent", "not idempotent")
+	describe(a.OpenWorldHint, "open world", "closed world")
+	return hints
+}
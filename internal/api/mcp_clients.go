@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp_client"
 	"net/http"
 )
@@ -39,6 +40,34 @@ func createMcpClientHandler(mcpClientService *mcp_client.McpClientService) gin.H
 	}
 }
 
+// updateMcpClientHandler changes the description and the allow list of an MCP client.
+// The client's open MCP sessions are told to list the tools, prompts and resources again.
+func updateMcpClientHandler(
+	mcpClientService *mcp_client.McpClientService, mcpService *mcp.MCPService,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		// the request only contains the settings to change, all others keep their current values
+		var req struct {
+			Description *string  `json:"description"`
+			AllowList   []string `json:"allow_list"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		client, err := mcpClientService.UpdateClient(name, req.Description, req.AllowList)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if req.AllowList != nil {
+			mcpService.NotifyClientAccessChanged(client.Name)
+		}
+		c.JSON(http.StatusOK, client)
+	}
+}
+
 func deleteMcpClientHandler(mcpClientService *mcp_client.McpClientService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		name := c.Param("name")
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"net/http"
+)
+
+func listPromptsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		server := c.Query("server")
+		var (
+			prompts []model.Prompt
+			err     error
+		)
+		if server == "" {
+			// no server specified, list all prompts
+			prompts, err = mcpService.ListPrompts()
+		} else {
+			// server specified, list prompts for that server
+			prompts, err = mcpService.ListPromptsByServer(server)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, prompts)
+	}
+}
@@ -1,12 +1,21 @@
 package api
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"net/http"
 )
 
+// serverErrorStatus returns the HTTP status of the response to a failed registration or update of an MCP server.
+func serverErrorStatus(err error) int {
+	if errors.Is(err, mcp.ErrStdioNotAllowed) {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
 func registerServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req model.McpServer
@@ -15,10 +24,31 @@ func registerServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 			return
 		}
 		if err := mcpService.RegisterMcpServer(c, &req); err != nil {
+			c.JSON(serverErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, req.Redacted())
+	}
+}
+
+func updateServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		s, err := mcpService.GetMcpServer(name)
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusCreated, req)
+		// the request only contains the settings to change, all others keep their current values
+		if err := c.ShouldBindJSON(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := mcpService.UpdateMcpServer(c, name, s); err != nil {
+			c.JSON(serverErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, s.Redacted())
 	}
 }
 
@@ -40,6 +70,33 @@ func listServersHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		for i := range servers {
+			servers[i] = servers[i].Redacted()
+		}
 		c.JSON(http.StatusOK, servers)
 	}
 }
+
+func getServerHealthHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		health, err := mcpService.GetMcpServerHealth(name)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, health)
+	}
+}
+
+func syncServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		result, err := mcpService.SyncMcpServer(c, name)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/types"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -86,3 +87,58 @@ func getToolHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 		c.JSON(http.StatusOK, tool)
 	}
 }
+
+// updateToolHandler changes the overrides of the tool with the given name, eg- its alias or description.
+func updateToolHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// tool name has to be supplied as a query param because it contains slash.
+		name := c.Query("name")
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' query parameter"})
+			return
+		}
+		// the request only contains the overrides to change, all others keep their current values
+		var req types.ToolOverridesUpdate
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		tool, err := mcpService.UpdateToolOverrides(name, &req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update tool: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, tool)
+	}
+}
+
+// setToolEnabledHandler enables or disables the tool named in the JSON body.
+func setToolEnabledHandler(mcpService *mcp.MCPService, enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if req.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' field in request body"})
+			return
+		}
+		var (
+			tool *model.Tool
+			err  error
+		)
+		if enabled {
+			tool, err = mcpService.EnableTool(req.Name)
+		} else {
+			tool, err = mcpService.DisableTool(req.Name)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, tool)
+	}
+}
@@ -16,6 +16,9 @@ import (
 
 const V0PathPrefix = "/api/v0"
 
+// mcpSessionIdHeader is the header carrying the ID of the MCP session in the streamable HTTP transport
+const mcpSessionIdHeader = "Mcp-Session-Id"
+
 type ServerOptions struct {
 	// Port is the HTTP ports to bind the server to
 	Port string
@@ -189,6 +192,39 @@ func checkAuthForMcpProxyAccess(
 	}
 }
 
+// resolveToolset is middleware for the MCP servers of toolsets that looks up the toolset named in the path
+// and injects it in the request's context, so that the MCP proxy only exposes the toolset's tools.
+func resolveToolset(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, err := mcpService.GetToolset(c.Param("toolset"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx := context.WithValue(c.Request.Context(), "toolset", t)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// checkMcpSessionEndpoint is middleware for the MCP proxy that rejects requests made within a session
+// initialized on another MCP endpoint, eg- a session of a toolset's MCP server used on `/mcp`.
+// Clients are told the session doesn't exist, so that they start a new one on this endpoint.
+func checkMcpSessionEndpoint(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID := c.GetHeader(mcpSessionIdHeader)
+		if sessionID == "" {
+			c.Next()
+			return
+		}
+		if err := mcpService.CheckSessionEndpoint(c.Request.Context(), sessionID); err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
 // requireServerMode is middleware that checks if the server is in a specific mode.
 // If not, the request is rejected with a 403 Forbidden status.
 func requireServerMode(configService *config.ServerConfigService, m model.ServerMode) gin.HandlerFunc {
@@ -230,11 +266,27 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 	checkMcpClientAuth := checkAuthForMcpProxyAccess(opts.ConfigService, opts.MCPClientService)
 
 	// Set up the MCP proxy server on /mcp
-	streamableHttpServer := server.NewStreamableHTTPServer(opts.MCPProxyServer)
+	// Each downstream MCP session gets its own upstream sessions, which are closed when it's terminated.
+	sessionIdManager := opts.MCPService.SessionIdManager()
+	streamableHttpServer := server.NewStreamableHTTPServer(
+		opts.MCPProxyServer,
+		server.WithSessionIdManager(sessionIdManager),
+	)
 	r.Any(
 		"/mcp",
 		requireInit,
 		checkMcpClientAuth,
+		checkMcpSessionEndpoint(opts.MCPService),
+		gin.WrapH(streamableHttpServer),
+	)
+	// Each toolset is served as its own MCP server on /mcp/<toolset>, backed by the same proxy server.
+	// Sessions are bound to the endpoint they were initialized on.
+	r.Any(
+		"/mcp/:toolset",
+		requireInit,
+		checkMcpClientAuth,
+		resolveToolset(opts.MCPService),
+		checkMcpSessionEndpoint(opts.MCPService),
 		gin.WrapH(streamableHttpServer),
 	)
 
@@ -242,11 +294,22 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 	apiV0 := r.Group(V0PathPrefix, requireInit, checkUserAuth)
 	{
 		apiV0.POST("/servers", registerServerHandler(opts.MCPService))
+		apiV0.PATCH("/servers/:name", updateServerHandler(opts.MCPService))
 		apiV0.DELETE("/servers/:name", deregisterServerHandler(opts.MCPService))
 		apiV0.GET("/servers", listServersHandler(opts.MCPService))
+		apiV0.GET("/servers/:name/health", getServerHealthHandler(opts.MCPService))
+		apiV0.POST("/servers/:name/sync", syncServerHandler(opts.MCPService))
 		apiV0.GET("/tools", listToolsHandler(opts.MCPService))
 		apiV0.POST("/tools/invoke", invokeToolHandler(opts.MCPService))
+		apiV0.POST("/tools/enable", setToolEnabledHandler(opts.MCPService, true))
+		apiV0.POST("/tools/disable", setToolEnabledHandler(opts.MCPService, false))
 		apiV0.GET("/tool", getToolHandler(opts.MCPService))
+		apiV0.PATCH("/tool", updateToolHandler(opts.MCPService))
+		apiV0.GET("/prompts", listPromptsHandler(opts.MCPService))
+		apiV0.POST("/toolsets", createToolsetHandler(opts.MCPService))
+		apiV0.GET("/toolsets", listToolsetsHandler(opts.MCPService))
+		apiV0.GET("/toolsets/:name", getToolsetHandler(opts.MCPService))
+		apiV0.DELETE("/toolsets/:name", deleteToolsetHandler(opts.MCPService))
 
 		apiV0.GET(
 			"/clients",
@@ -258,6 +321,11 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 			requireServerMode(opts.ConfigService, model.ModeProd),
 			createMcpClientHandler(opts.MCPClientService),
 		)
+		apiV0.PATCH(
+			"/clients/:name",
+			requireServerMode(opts.ConfigService, model.ModeProd),
+			updateMcpClientHandler(opts.MCPClientService, opts.MCPService),
+		)
 		apiV0.DELETE(
 			"/clients/:name",
 			requireServerMode(opts.ConfigService, model.ModeProd),
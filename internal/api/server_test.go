@@ -0,0 +1,57 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMcpSessionBoundToEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mcpService, _, proxy := newTestMCPService(t)
+	if err := mcpService.CreateToolset(&model.Toolset{Name: "coding", Tools: []byte(`["github"]`)}); err != nil {
+		t.Fatalf("failed to create toolset: %v", err)
+	}
+
+	sessionIdManager := mcpService.SessionIdManager()
+	streamableHttpServer := server.NewStreamableHTTPServer(proxy, server.WithSessionIdManager(sessionIdManager))
+	r := gin.New()
+	r.Any("/mcp", checkMcpSessionEndpoint(mcpService), gin.WrapH(streamableHttpServer))
+	r.Any(
+		"/mcp/:toolset",
+		resolveToolset(mcpService),
+		checkMcpSessionEndpoint(mcpService),
+		gin.WrapH(streamableHttpServer),
+	)
+
+	post := func(path, sessionID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if sessionID != "" {
+			req.Header.Set(mcpSessionIdHeader, sessionID)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/mcp/coding", "", `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {
+		"protocolVersion": "2025-03-26", "capabilities": {}, "clientInfo": {"name": "test", "version": "1.0.0"}
+	}}`)
+	sessionID := w.Header().Get(mcpSessionIdHeader)
+	if w.Code != http.StatusOK || sessionID == "" {
+		t.Fatalf("initialize failed with status %d: %s", w.Code, w.Body.String())
+	}
+
+	listTools := `{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`
+	if w := post("/mcp/coding", sessionID, listTools); w.Code != http.StatusOK {
+		t.Errorf("request on the session's endpoint failed with status %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("/mcp", sessionID, listTools); w.Code != http.StatusNotFound {
+		t.Errorf("request on the main endpoint got status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"net/http"
+)
+
+func createToolsetHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Name         string   `json:"name"`
+			Description  string   `json:"description"`
+			Instructions string   `json:"instructions"`
+			Tools        []string `json:"tools"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if req.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			return
+		}
+		tools, err := json.Marshal(req.Tools)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tools"})
+			return
+		}
+		t := &model.Toolset{
+			Name:         req.Name,
+			Description:  req.Description,
+			Instructions: req.Instructions,
+			Tools:        tools,
+		}
+		if err := mcpService.CreateToolset(t); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, t)
+	}
+}
+
+func listToolsetsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		toolsets, err := mcpService.ListToolsets()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, toolsets)
+	}
+}
+
+func getToolsetHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, err := mcpService.GetToolset(c.Param("name"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, t)
+	}
+}
+
+func deleteToolsetHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := mcpService.DeleteToolset(c.Param("name")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
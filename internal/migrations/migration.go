@@ -11,9 +11,28 @@ func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(&model.McpServer{}); err != nil {
 		return fmt.Errorf("auto‑migration failed for McpServer model: %v", err)
 	}
+	// Tool names used to be unique across all MCP servers, which prevented two servers from
+	// providing tools with the same name. They are now only unique within a server.
+	if db.Migrator().HasIndex(&model.Tool{}, "idx_tools_name") {
+		if err := db.Migrator().DropIndex(&model.Tool{}, "idx_tools_name"); err != nil {
+			return fmt.Errorf("failed to drop unique index on Tool name: %v", err)
+		}
+	}
 	if err := db.AutoMigrate(&model.Tool{}); err != nil {
 		return fmt.Errorf("auto‑migration failed for Tool model: %v", err)
 	}
+	if err := db.AutoMigrate(&model.Resource{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for Resource model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ResourceTemplate{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ResourceTemplate model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Prompt{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for Prompt model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Toolset{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for Toolset model: %v", err)
+	}
 	if err := db.AutoMigrate(&model.ServerConfig{}); err != nil {
 		return fmt.Errorf("auto‑migration failed for ServerConfig model: %v", err)
 	}
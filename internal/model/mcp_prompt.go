@@ -0,0 +1,21 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Prompt is a prompt or prompt template provided by an upstream MCP server.
+type Prompt struct {
+	gorm.Model
+
+	// Name is unique amongst the prompts of the same MCP server
+	Name        string `json:"name" gorm:"uniqueIndex:idx_prompts_server_id_name,priority:2;not null"`
+	Description string `json:"description"`
+
+	// Arguments contains the arguments accepted by the prompt, if it is a template.
+	Arguments datatypes.JSON `json:"arguments,omitempty" gorm:"type:jsonb"`
+
+	ServerID uint      `json:"-" gorm:"uniqueIndex:idx_prompts_server_id_name,priority:1;not null"`
+	Server   McpServer `json:"-" gorm:"foreignKey:ServerID;references:ID"`
+}
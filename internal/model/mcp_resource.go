@@ -0,0 +1,42 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Resource is a resource provided by an upstream MCP server.
+type Resource struct {
+	gorm.Model
+
+	// URI is the URI of the resource as provided by the MCP server.
+	// It is unique amongst the resources of the same MCP server.
+	URI         string `json:"uri" gorm:"uniqueIndex:idx_resources_server_id_uri,priority:2;not null"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mime_type,omitempty"`
+
+	// Annotations contains the hints provided by the MCP server about the resource (eg- audience).
+	Annotations datatypes.JSON `json:"annotations,omitempty" gorm:"type:jsonb"`
+
+	ServerID uint      `json:"-" gorm:"uniqueIndex:idx_resources_server_id_uri,priority:1;not null"`
+	Server   McpServer `json:"-" gorm:"foreignKey:ServerID;references:ID"`
+}
+
+// ResourceTemplate is an RFC 6570 URI template describing a family of resources provided by an upstream MCP server.
+type ResourceTemplate struct {
+	gorm.Model
+
+	// URITemplate is the URI template as provided by the MCP server.
+	// It is unique amongst the resource templates of the same MCP server.
+	URITemplate string `json:"uri_template" gorm:"uniqueIndex:idx_resource_templates_server_id_uri_template,priority:2;not null"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mime_type,omitempty"`
+
+	// Annotations contains the hints provided by the MCP server about the resources (eg- audience).
+	Annotations datatypes.JSON `json:"annotations,omitempty" gorm:"type:jsonb"`
+
+	ServerID uint      `json:"-" gorm:"uniqueIndex:idx_resource_templates_server_id_uri_template,priority:1;not null"`
+	Server   McpServer `json:"-" gorm:"foreignKey:ServerID;references:ID"`
+}
@@ -1,6 +1,45 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"encoding/json"
+	"fmt"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+// McpServerTransport is the transport protocol MCPJungle uses to communicate with an upstream MCP server.
+type McpServerTransport string
+
+const (
+	// TransportStreamableHTTP is used for MCP servers exposed over the streamable HTTP transport.
+	TransportStreamableHTTP McpServerTransport = "streamable_http"
+
+	// TransportSSE is used for MCP servers that only support the legacy HTTP+SSE transport.
+	TransportSSE McpServerTransport = "sse"
+
+	// TransportStdio is used for MCP servers that run as a local process and talk over stdin/stdout.
+	// MCPJungle spawns and supervises the process itself.
+	TransportStdio McpServerTransport = "stdio"
+)
+
+// McpServerAuthType is the scheme MCPJungle uses to authenticate with an HTTP-based upstream MCP server.
+type McpServerAuthType string
+
+const (
+	// AuthTypeBearer sends BearerToken in the "Authorization: Bearer <token>" header.
+	AuthTypeBearer McpServerAuthType = "bearer"
+
+	// AuthTypeBasic sends BasicAuthUsername and BasicAuthPassword using HTTP basic authentication.
+	AuthTypeBasic McpServerAuthType = "basic"
+
+	// AuthTypeHeader sends AuthHeaderValue in a custom header named AuthHeaderName (eg- X-API-Key).
+	AuthTypeHeader McpServerAuthType = "header"
+
+	// AuthTypeOAuth2 obtains access tokens from OAuthTokenURL using the OAuth 2.0 client credentials grant
+	// and sends them as bearer tokens. Tokens are refreshed before they expire.
+	AuthTypeOAuth2 McpServerAuthType = "oauth2"
+)
 
 type McpServer struct {
 	gorm.Model
@@ -8,12 +47,250 @@ type McpServer struct {
 	Name        string `json:"name" gorm:"uniqueIndex;not null"`
 	Description string `json:"description"`
 
+	// Version and Instructions are reported by the MCP server itself when MCPJungle connects to it.
+	Version      string `json:"version,omitempty"`
+	Instructions string `json:"instructions,omitempty" gorm:"type:text"`
+
+	// Transport determines how MCPJungle connects to this MCP server.
+	// If it is not specified during registration, MCPJungle detects the HTTP-based transport supported by the server.
+	Transport McpServerTransport `json:"transport" gorm:"type:varchar(20);not null;default:'streamable_http'"`
+
 	// URL must be a valid http/https URL.
-	// MCPJungle only supports streamable HTTP transport as of now.
+	// It is only used by HTTP-based transports and is empty for stdio servers.
 	URL string `json:"url" gorm:"not null"`
 
 	// TODO: Store the bearer token in a more secure way, e.g., encrypted in the database.
 	// BearerToken is an optional token used for authenticating requests to the MCP server.
 	// If present, it will be used to set the Authorization header in all requests to this MCP server.
 	BearerToken string `json:"bearer_token,omitempty" gorm:"type:text"`
+
+	// AuthType determines how MCPJungle authenticates with the MCP server.
+	// If empty, the bearer scheme is used when a BearerToken is present and no authentication is done otherwise.
+	AuthType McpServerAuthType `json:"auth_type,omitempty" gorm:"type:varchar(20)"`
+
+	// BasicAuthUsername and BasicAuthPassword are the credentials used by the basic auth type.
+	BasicAuthUsername string `json:"basic_auth_username,omitempty"`
+	BasicAuthPassword string `json:"basic_auth_password,omitempty" gorm:"type:text"`
+
+	// AuthHeaderName and AuthHeaderValue are the header and secret used by the header auth type.
+	AuthHeaderName  string `json:"auth_header_name,omitempty"`
+	AuthHeaderValue string `json:"auth_header_value,omitempty" gorm:"type:text"`
+
+	// OAuthTokenURL, OAuthClientID, OAuthClientSecret and OAuthScopes are the client credentials grant
+	// settings used by the oauth2 auth type. OAuthScopes is a space-separated list of scopes.
+	OAuthTokenURL     string `json:"oauth_token_url,omitempty"`
+	OAuthClientID     string `json:"oauth_client_id,omitempty"`
+	OAuthClientSecret string `json:"oauth_client_secret,omitempty" gorm:"type:text"`
+	OAuthScopes       string `json:"oauth_scopes,omitempty"`
+
+	// TLSClientCert and TLSClientKey are the PEM-encoded certificate and private key MCPJungle presents
+	// to the MCP server for mutual TLS. Both must be set for the client certificate to be used.
+	TLSClientCert string `json:"tls_client_cert,omitempty" gorm:"type:text"`
+	TLSClientKey  string `json:"tls_client_key,omitempty" gorm:"type:text"`
+	// TLSCACert is a PEM-encoded bundle of CA certificates used to verify the MCP server's certificate.
+	// If empty, the system's root CAs are used.
+	TLSCACert string `json:"tls_ca_cert,omitempty" gorm:"type:text"`
+	// TLSServerName overrides the server name used to verify the MCP server's certificate (and sent via SNI).
+	// If empty, the host of URL is used.
+	TLSServerName string `json:"tls_server_name,omitempty"`
+
+	// Headers contains additional HTTP headers sent in all requests to the MCP server, stored as a JSON object.
+	// The authentication header takes precedence over a header with the same name in this map.
+	Headers datatypes.JSON `json:"headers,omitempty" gorm:"type:jsonb"`
+
+	// Command is the executable that runs a stdio MCP server (eg- npx, uvx, /usr/local/bin/my-server).
+	Command string `json:"command,omitempty"`
+	// Args contains the list of arguments passed to Command, stored as a JSON array.
+	Args datatypes.JSON `json:"args,omitempty" gorm:"type:jsonb"`
+	// Env contains additional environment variables for the process, stored as a JSON object.
+	// These are added on top of the environment MCPJungle itself runs with.
+	Env datatypes.JSON `json:"env,omitempty" gorm:"type:jsonb"`
+	// WorkDir is the working directory of the process.
+	// If empty, the process inherits the working directory of MCPJungle.
+	WorkDir string `json:"work_dir,omitempty"`
+
+	// ConnectTimeout is the maximum number of seconds MCPJungle waits to establish a session with the MCP server.
+	// If zero, DefaultConnectTimeout is used.
+	ConnectTimeout int `json:"connect_timeout,omitempty"`
+	// CallTimeout is the maximum number of seconds a single call to the MCP server may take.
+	// If zero, DefaultCallTimeout is used.
+	CallTimeout int `json:"call_timeout,omitempty"`
+	// MaxRetries is the number of times a failed call to a read-only tool of this server is retried.
+	// Only failures to reach the server or get a response from it are retried.
+	// Calls to tools that are not marked as read-only are never retried because they may have side effects.
+	MaxRetries int `json:"max_retries,omitempty"`
+
+	// Process contains the live status of the process backing a stdio MCP server.
+	// It is not stored in the DB and is only populated when listing servers.
+	Process *ProcessStatus `json:"process,omitempty" gorm:"-"`
+
+	// CircuitBreaker contains the live state of the circuit breaker guarding calls to this server.
+	// It is not stored in the DB and is only populated when listing servers.
+	CircuitBreaker *CircuitBreakerStatus `json:"circuit_breaker,omitempty" gorm:"-"`
+
+	// Health contains the outcome of the latest background health check of this server.
+	// It is not stored in the DB and is only populated when listing servers.
+	Health *ServerHealth `json:"health,omitempty" gorm:"-"`
+}
+
+const (
+	// DefaultConnectTimeout is used for MCP servers that don't specify a ConnectTimeout.
+	DefaultConnectTimeout = 30 * time.Second
+	// DefaultCallTimeout is used for MCP servers that don't specify a CallTimeout.
+	DefaultCallTimeout = 5 * time.Minute
+)
+
+// GetConnectTimeout returns the time allowed for establishing a session with the MCP server.
+func (s *McpServer) GetConnectTimeout() time.Duration {
+	if s.ConnectTimeout <= 0 {
+		return DefaultConnectTimeout
+	}
+	return time.Duration(s.ConnectTimeout) * time.Second
+}
+
+// GetCallTimeout returns the time allowed for a single call to the MCP server.
+func (s *McpServer) GetCallTimeout() time.Duration {
+	if s.CallTimeout <= 0 {
+		return DefaultCallTimeout
+	}
+	return time.Duration(s.CallTimeout) * time.Second
+}
+
+// GetArgs returns the list of command arguments of a stdio MCP server.
+func (s *McpServer) GetArgs() ([]string, error) {
+	var args []string
+	if len(s.Args) == 0 {
+		return args, nil
+	}
+	if err := json.Unmarshal(s.Args, &args); err != nil {
+		return nil, fmt.Errorf("invalid args for MCP server %s: %w", s.Name, err)
+	}
+	return args, nil
+}
+
+// GetEnv returns the environment variables of a stdio MCP server as a list of KEY=VALUE pairs.
+func (s *McpServer) GetEnv() ([]string, error) {
+	var env map[string]string
+	if len(s.Env) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(s.Env, &env); err != nil {
+		return nil, fmt.Errorf("invalid env for MCP server %s: %w", s.Name, err)
+	}
+	pairs := make([]string, 0, len(env))
+	for k, v := range env {
+		pairs = append(pairs, k+"="+v)
+	}
+	return pairs, nil
+}
+
+// GetHeaders returns the additional HTTP headers sent to an HTTP-based MCP server.
+func (s *McpServer) GetHeaders() (map[string]string, error) {
+	headers := make(map[string]string)
+	if len(s.Headers) == 0 {
+		return headers, nil
+	}
+	if err := json.Unmarshal(s.Headers, &headers); err != nil {
+		return nil, fmt.Errorf("invalid headers for MCP server %s: %w", s.Name, err)
+	}
+	return headers, nil
+}
+
+// ProcessState represents the lifecycle state of a supervised stdio MCP server process.
+type ProcessState string
+
+const (
+	ProcessStarting   ProcessState = "starting"
+	ProcessRunning    ProcessState = "running"
+	ProcessRestarting ProcessState = "restarting"
+	ProcessStopped    ProcessState = "stopped"
+)
+
+// ProcessStatus describes the current state of a stdio MCP server process managed by MCPJungle.
+type ProcessStatus struct {
+	State     ProcessState `json:"state"`
+	PID       int          `json:"pid,omitempty"`
+	Restarts  int          `json:"restarts"`
+	StartedAt *time.Time   `json:"started_at,omitempty"`
+	LastError string       `json:"last_error,omitempty"`
+}
+
+// CircuitBreakerState represents the state of the circuit breaker guarding calls to an MCP server.
+type CircuitBreakerState string
+
+const (
+	// CircuitClosed means that calls are sent to the MCP server normally.
+	CircuitClosed CircuitBreakerState = "closed"
+	// CircuitOpen means that the MCP server failed repeatedly and calls fail fast without reaching it.
+	CircuitOpen CircuitBreakerState = "open"
+	// CircuitHalfOpen means that a single trial call is let through to check whether the MCP server recovered.
+	CircuitHalfOpen CircuitBreakerState = "half_open"
+)
+
+// CircuitBreakerStatus describes the circuit breaker guarding calls to an MCP server.
+type CircuitBreakerStatus struct {
+	State CircuitBreakerState `json:"state"`
+	// Failures is the number of consecutive failed calls to the MCP server.
+	Failures  int        `json:"failures"`
+	OpenedAt  *time.Time `json:"opened_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// HealthStatus represents the outcome of the health checks MCPJungle runs against an MCP server.
+type HealthStatus string
+
+const (
+	// HealthUnknown means that the MCP server hasn't been checked yet.
+	HealthUnknown HealthStatus = "unknown"
+	// HealthHealthy means that the MCP server responded to the latest ping.
+	HealthHealthy HealthStatus = "healthy"
+	// HealthUnhealthy means that the latest ping to the MCP server failed.
+	HealthUnhealthy HealthStatus = "unhealthy"
+)
+
+// ServerHealth describes the health of an MCP server as seen by MCPJungle's background health checks.
+type ServerHealth struct {
+	Status HealthStatus `json:"status"`
+	// LastChecked is the time of the latest health check.
+	LastChecked *time.Time `json:"last_checked,omitempty"`
+	// LastSeen is the time of the latest successful health check.
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+	// LatencyMs is the round-trip time of the latest successful ping in milliseconds.
+	LatencyMs int64  `json:"latency_ms"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Redacted returns a copy of the server without its secrets.
+// The secrets are only ever sent to the MCP server, they must never be returned to API clients.
+func (s McpServer) Redacted() McpServer {
+	s.BearerToken = ""
+	s.BasicAuthPassword = ""
+	s.AuthHeaderValue = ""
+	s.OAuthClientSecret = ""
+	s.TLSClientKey = ""
+	s.Env = redactEnv(s.Env)
+	return s
+}
+
+// redactedValue replaces the values of environment variables returned to API clients.
+const redactedValue = "<redacted>"
+
+// redactEnv keeps the names of a stdio MCP server's environment variables but hides their values,
+// which commonly hold API keys.
+func redactEnv(env datatypes.JSON) datatypes.JSON {
+	if len(env) == 0 {
+		return env
+	}
+	var vars map[string]string
+	if err := json.Unmarshal(env, &vars); err != nil {
+		return nil
+	}
+	for k := range vars {
+		vars[k] = redactedValue
+	}
+	redacted, err := json.Marshal(vars)
+	if err != nil {
+		return nil
+	}
+	return redacted
 }
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -8,10 +9,51 @@ import (
 type Tool struct {
 	gorm.Model
 
-	Name        string         `json:"name" gorm:"uniqueIndex;not null"`
+	// Name is unique amongst the tools of the same MCP server
+	Name string `json:"name" gorm:"uniqueIndex:idx_tools_server_id_name,priority:2;not null"`
+	// Title is the human-readable name of the tool, if the MCP server provides one
+	Title       string         `json:"title,omitempty"`
 	Description string         `json:"description"`
 	InputSchema datatypes.JSON `json:"input_schema" gorm:"type:jsonb"`
+	// OutputSchema is the JSON schema of the structured content returned by the tool, if any
+	OutputSchema datatypes.JSON `json:"output_schema,omitempty" gorm:"type:jsonb"`
 
-	ServerID uint      `json:"-" gorm:"not null"`
+	// Annotations contains the hints provided by the MCP server about the tool's behavior (eg- readOnlyHint).
+	Annotations datatypes.JSON `json:"annotations,omitempty" gorm:"type:jsonb"`
+
+	// Enabled is false if an admin disabled the tool, which hides it from MCP clients and rejects its calls.
+	// It is kept when the tool is synced.
+	Enabled bool `json:"enabled" gorm:"not null;default:true"`
+
+	// Overrides are set by admins to change how the tool is exposed. They are kept when the tool is synced.
+	Overrides ToolOverrides `json:"overrides" gorm:"embedded;embeddedPrefix:override_"`
+
+	ServerID uint      `json:"-" gorm:"uniqueIndex:idx_tools_server_id_name,priority:1;not null"`
 	Server   McpServer `json:"-" gorm:"foreignKey:ServerID;references:ID"`
 }
+
+// ToolOverrides change how a tool is exposed by MCPJungle, without changing the MCP server that provides it.
+// Empty fields leave the tool's definition as provided by the MCP server.
+type ToolOverrides struct {
+	// Alias replaces the name of the tool (without the server name).
+	// The MCP server is still asked to call the tool by its own name.
+	Alias       string `json:"alias,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// ParameterDescriptions replaces the descriptions of the tool's input parameters, by parameter name.
+	ParameterDescriptions datatypes.JSON `json:"parameter_descriptions,omitempty" gorm:"type:jsonb"`
+}
+
+// IsReadOnly returns true if the MCP server has marked the tool as not modifying its environment.
+func (t *Tool) IsReadOnly() bool {
+	if len(t.Annotations) == 0 {
+		return false
+	}
+	var annotations struct {
+		ReadOnlyHint *bool `json:"readOnlyHint"`
+	}
+	if err := json.Unmarshal(t.Annotations, &annotations); err != nil {
+		return false
+	}
+	return annotations.ReadOnlyHint != nil && *annotations.ReadOnlyHint
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Toolset is a named selection of the tools in the registry.
+// Each toolset is served as its own MCP server at `/mcp/<name>`, which only exposes the selected tools.
+type Toolset struct {
+	gorm.Model
+
+	Name        string `json:"name" gorm:"uniqueIndex;not null"`
+	Description string `json:"description"`
+
+	// Instructions are sent to MCP clients when they connect to the toolset's MCP server.
+	Instructions string `json:"instructions"`
+
+	// Tools is a JSON array of the entries that select the tools of the toolset.
+	// An entry is either the canonical name of a tool (eg- `github/git_commit`), or a pattern in which
+	// `*` matches any characters (eg- `github/*`). An entry that is just a server name (or a pattern
+	// matching server names) selects all the tools of the matching servers.
+	Tools datatypes.JSON `json:"tools" gorm:"type:jsonb;not null"`
+}
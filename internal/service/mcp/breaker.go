@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"sync"
+	"time"
+)
+
+const (
+	// breakerFailureThreshold is the number of consecutive failures after which the circuit opens.
+	breakerFailureThreshold = 5
+	// breakerCooldown is how long the circuit stays open before a trial call is let through.
+	breakerCooldown = 30 * time.Second
+)
+
+// upstreamError is returned when a call could not reach an upstream MCP server or got no response from it
+// (eg- connection failures, timeouts), as opposed to errors returned by the server itself.
+// Only such errors are retried and counted by the circuit breaker.
+type upstreamError struct {
+	err error
+}
+
+func (e *upstreamError) Error() string {
+	return e.err.Error()
+}
+
+func (e *upstreamError) Unwrap() error {
+	return e.err
+}
+
+func isUpstreamError(err error) bool {
+	var ue *upstreamError
+	return errors.As(err, &ue)
+}
+
+// breakerSet holds the circuit breakers of all upstream MCP servers, keyed by server name.
+type breakerSet struct {
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func newBreakerSet() *breakerSet {
+	return &breakerSet{breakers: make(map[string]*circuitBreaker)}
+}
+
+// get returns the circuit breaker of the given server, creating it if necessary.
+func (bs *breakerSet) get(name string) *circuitBreaker {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	cb, ok := bs.breakers[name]
+	if !ok {
+		cb = &circuitBreaker{server: name, state: model.CircuitClosed}
+		bs.breakers[name] = cb
+	}
+	return cb
+}
+
+// remove forgets the circuit breaker of the given server.
+func (bs *breakerSet) remove(name string) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	delete(bs.breakers, name)
+}
+
+// circuitBreaker stops calls to an upstream MCP server after it fails repeatedly, so that a flapping
+// server is given time to recover and callers get a quick error instead of waiting for timeouts.
+// After a cooldown, a single trial call is let through. If it succeeds the circuit closes again,
+// otherwise it stays open for another cooldown.
+type circuitBreaker struct {
+	server string
+
+	mu        sync.Mutex
+	state     model.CircuitBreakerState
+	failures  int
+	openedAt  time.Time
+	lastError string
+	// probing is true while the trial call of a half-open circuit is in flight
+	probing bool
+}
+
+// allow returns an error if calls to the server must currently fail fast.
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case model.CircuitOpen:
+		if wait := breakerCooldown - time.Since(cb.openedAt); wait > 0 {
+			return fmt.Errorf(
+				"MCP server %s is unavailable: circuit breaker is open after %d consecutive failures, "+
+					"retrying in %s (last error: %s)",
+				cb.server, cb.failures, wait.Round(time.Second), cb.lastError,
+			)
+		}
+		cb.state = model.CircuitHalfOpen
+		cb.probing = true
+		return nil
+	case model.CircuitHalfOpen:
+		if cb.probing {
+			return fmt.Errorf(
+				"MCP server %s is unavailable: circuit breaker is waiting for a trial call to complete "+
+					"(last error: %s)",
+				cb.server, cb.lastError,
+			)
+		}
+		cb.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of a call that was allowed through.
+func (cb *circuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.probing = false
+
+	if err == nil || !isUpstreamError(err) {
+		// the server responded, so it is healthy even if the call itself failed
+		cb.state = model.CircuitClosed
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	cb.lastError = err.Error()
+	if cb.state == model.CircuitHalfOpen || cb.failures >= breakerFailureThreshold {
+		cb.state = model.CircuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// abandon is called instead of record when the outcome of an allowed call says nothing about
+// the server's health, eg- because the caller cancelled it.
+func (cb *circuitBreaker) abandon() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.probing = false
+}
+
+// status returns the current state of the breaker.
+func (cb *circuitBreaker) status() *model.CircuitBreakerStatus {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	st := &model.CircuitBreakerStatus{
+		State:     cb.state,
+		Failures:  cb.failures,
+		LastError: cb.lastError,
+	}
+	if cb.state != model.CircuitClosed {
+		openedAt := cb.openedAt
+		st.OpenedAt = &openedAt
+	}
+	return st
+}
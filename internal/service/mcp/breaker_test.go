@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"errors"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	upstreamErr := &upstreamError{err: errors.New("connection refused")}
+
+	t.Run("opens after consecutive upstream failures", func(t *testing.T) {
+		cb := &circuitBreaker{server: "s", state: model.CircuitClosed}
+		for i := 0; i < breakerFailureThreshold; i++ {
+			if err := cb.allow(); err != nil {
+				t.Fatalf("allow() error = %v before reaching the failure threshold", err)
+			}
+			cb.record(upstreamErr)
+		}
+		if got := cb.status().State; got != model.CircuitOpen {
+			t.Fatalf("state = %s, want %s", got, model.CircuitOpen)
+		}
+		if err := cb.allow(); err == nil {
+			t.Error("allow() error = nil while the circuit is open")
+		}
+	})
+
+	t.Run("errors returned by the server don't count", func(t *testing.T) {
+		cb := &circuitBreaker{server: "s", state: model.CircuitClosed}
+		for i := 0; i < breakerFailureThreshold*2; i++ {
+			cb.record(errors.New("tool not found"))
+		}
+		if got := cb.status().State; got != model.CircuitClosed {
+			t.Errorf("state = %s, want %s", got, model.CircuitClosed)
+		}
+	})
+
+	t.Run("success resets failures", func(t *testing.T) {
+		cb := &circuitBreaker{server: "s", state: model.CircuitClosed}
+		for i := 0; i < breakerFailureThreshold-1; i++ {
+			cb.record(upstreamErr)
+		}
+		cb.record(nil)
+		cb.record(upstreamErr)
+		if st := cb.status(); st.State != model.CircuitClosed || st.Failures != 1 {
+			t.Errorf("status = %+v, want closed with 1 failure", st)
+		}
+	})
+
+	t.Run("half-open lets a single trial call through", func(t *testing.T) {
+		cb := &circuitBreaker{
+			server:   "s",
+			state:    model.CircuitOpen,
+			failures: breakerFailureThreshold,
+			openedAt: time.Now().Add(-breakerCooldown),
+		}
+		if err := cb.allow(); err != nil {
+			t.Fatalf("allow() error = %v after the cooldown", err)
+		}
+		if got := cb.status().State; got != model.CircuitHalfOpen {
+			t.Fatalf("state = %s, want %s", got, model.CircuitHalfOpen)
+		}
+		if err := cb.allow(); err == nil {
+			t.Error("allow() error = nil while the trial call is in flight")
+		}
+
+		cb.record(nil)
+		if got := cb.status().State; got != model.CircuitClosed {
+			t.Errorf("state = %s, want %s after a successful trial call", got, model.CircuitClosed)
+		}
+	})
+
+	t.Run("failed trial call reopens the circuit", func(t *testing.T) {
+		cb := &circuitBreaker{
+			server:   "s",
+			state:    model.CircuitOpen,
+			failures: breakerFailureThreshold,
+			openedAt: time.Now().Add(-breakerCooldown),
+		}
+		_ = cb.allow()
+		cb.record(upstreamErr)
+		if got := cb.status().State; got != model.CircuitOpen {
+			t.Errorf("state = %s, want %s after a failed trial call", got, model.CircuitOpen)
+		}
+		if err := cb.allow(); err == nil {
+			t.Error("allow() error = nil right after the circuit reopened")
+		}
+	})
+}
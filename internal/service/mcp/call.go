@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"log"
+	"time"
+)
+
+// callRetryBackoff is the delay before the first retry of a failed call, doubled for every subsequent retry.
+const callRetryBackoff = 500 * time.Millisecond
+
+// callMcpServer runs call against the upstream MCP server, enforcing the server's call timeout and circuit breaker.
+// If idempotent is true, calls that fail to reach the server are retried up to MaxRetries times with backoff.
+// Errors returned by the server itself are never retried.
+func (m *MCPService) callMcpServer(
+	ctx context.Context,
+	s *model.McpServer,
+	idempotent bool,
+	call func(ctx context.Context, c *client.Client) error,
+) error {
+	cb := m.breakers.get(s.Name)
+	retries := 0
+	if idempotent {
+		retries = max(s.MaxRetries, 0)
+	}
+	backoff := callRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		if err := cb.allow(); err != nil {
+			return err
+		}
+		err := m.callMcpServerOnce(ctx, s, call)
+		if err != nil && ctx.Err() != nil {
+			// the caller gave up, this says nothing about the health of the server
+			cb.abandon()
+			return err
+		}
+		cb.record(err)
+		if err == nil || !isUpstreamError(err) || attempt >= retries {
+			return err
+		}
+
+		log.Printf("[call] retrying failed call to MCP server %s (retry %d of %d): %v", s.Name, attempt+1, retries, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// callMcpServerOnce runs call with a client connected to the upstream MCP server, bounded by the server's call timeout.
+// Connections to HTTP-based servers are taken from the pool and evicted from it if they fail.
+// If ctx belongs to a downstream MCP session, the connection dedicated to that session is used.
+// If the upstream server has lost the session, the call is retried once on a fresh connection.
+func (m *MCPService) callMcpServerOnce(
+	ctx context.Context, s *model.McpServer, call func(ctx context.Context, c *client.Client) error,
+) error {
+	callCtx, cancel := context.WithTimeout(ctx, s.GetCallTimeout())
+	defer cancel()
+
+	err := m.dispatchCall(callCtx, s, call)
+	if err != nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return &upstreamError{
+			err: fmt.Errorf("call to MCP server %s timed out after %s", s.Name, s.GetCallTimeout()),
+		}
+	}
+	return err
+}
+
+func (m *MCPService) dispatchCall(
+	ctx context.Context, s *model.McpServer, call func(ctx context.Context, c *client.Client) error,
+) error {
+	if s.Transport == model.TransportStdio {
+		// the client of a stdio server is tied to its process, which is managed by the supervisor.
+		// All downstream sessions share the same process, so there is no per-session affinity.
+		c, err := m.stdioServers.client(s.Name)
+		if err != nil {
+			return &upstreamError{err: fmt.Errorf("failed to create connection to MCP server %s: %w", s.Name, err)}
+		}
+		if err := call(ctx, c); err != nil {
+			if isTransportError(err) {
+				return &upstreamError{err: err}
+			}
+			return err
+		}
+		return nil
+	}
+
+	var session string
+	if cs := server.ClientSessionFromContext(ctx); cs != nil {
+		session = cs.SessionID()
+	}
+
+	for attempt := 0; ; attempt++ {
+		c, err := m.conns.acquire(ctx, s, session)
+		if err != nil {
+			return &upstreamError{err: fmt.Errorf("failed to create connection to MCP server %s: %w", s.Name, err)}
+		}
+		err = call(ctx, c)
+		if err == nil || !isTransportError(err) || ctx.Err() != nil {
+			// a call that timed out or was cancelled leaves the connection usable
+			return err
+		}
+		m.conns.evict(s, session, c)
+		if attempt > 0 || !isSessionLostError(err) {
+			return &upstreamError{err: err}
+		}
+	}
+}
+
+// isReadOnlyTool returns true if the given tool of the MCP server is marked as read-only,
+// which means that calls to it can safely be retried.
+func (m *MCPService) isReadOnlyTool(s *model.McpServer, toolName string) bool {
+	var tool model.Tool
+	if err := m.db.Where("server_id = ? AND name = ?", s.ID, toolName).First(&tool).Error; err != nil {
+		return false
+	}
+	return tool.IsReadOnly()
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// healthCheckInterval is how often all registered MCP servers are pinged.
+	healthCheckInterval = 30 * time.Second
+	// healthCheckTimeout bounds the time an MCP server gets to respond to a health check ping.
+	healthCheckTimeout = 10 * time.Second
+)
+
+// healthChecker keeps the latest health status of every registered upstream MCP server.
+type healthChecker struct {
+	mu       sync.Mutex
+	statuses map[string]*model.ServerHealth
+
+	done chan struct{}
+}
+
+func newHealthChecker() *healthChecker {
+	return &healthChecker{
+		statuses: make(map[string]*model.ServerHealth),
+		done:     make(chan struct{}),
+	}
+}
+
+// get returns the health of the given server.
+// The status is unknown if the server hasn't been checked yet.
+func (h *healthChecker) get(name string) *model.ServerHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st, ok := h.statuses[name]
+	if !ok {
+		return &model.ServerHealth{Status: model.HealthUnknown}
+	}
+	c := *st
+	return &c
+}
+
+// record updates the health of the given server with the outcome of a ping.
+func (h *healthChecker) record(name string, checkedAt time.Time, latency time.Duration, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st, ok := h.statuses[name]
+	if !ok {
+		st = &model.ServerHealth{}
+		h.statuses[name] = st
+	}
+	st.LastChecked = &checkedAt
+	if err != nil {
+		st.Status = model.HealthUnhealthy
+		st.LastError = err.Error()
+		return
+	}
+	st.Status = model.HealthHealthy
+	st.LastSeen = &checkedAt
+	st.LatencyMs = latency.Milliseconds()
+	st.LastError = ""
+}
+
+// remove forgets the health of the given server.
+func (h *healthChecker) remove(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.statuses, name)
+}
+
+func (h *healthChecker) stop() {
+	close(h.done)
+}
+
+// runHealthChecks pings all registered MCP servers right away and then periodically, until Shutdown is called.
+func (m *MCPService) runHealthChecks() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		m.checkAllServersHealth()
+		select {
+		case <-m.health.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAllServersHealth pings all registered MCP servers concurrently and waits for the results.
+func (m *MCPService) checkAllServersHealth() {
+	var servers []model.McpServer
+	if err := m.db.Find(&servers).Error; err != nil {
+		log.Printf("[health] failed to list MCP servers from DB: %v", err)
+		return
+	}
+	var wg sync.WaitGroup
+	for i := range servers {
+		wg.Add(1)
+		go func(s *model.McpServer) {
+			defer wg.Done()
+			m.checkServerHealth(s)
+		}(&servers[i])
+	}
+	wg.Wait()
+}
+
+// checkServerHealth pings an MCP server and records the outcome.
+// The ping goes through the same connection and circuit breaker as tool calls,
+// so a server that recovers also closes its circuit breaker.
+// It doesn't count as use of the connection though, which still idles out if nothing else uses it.
+func (m *MCPService) checkServerHealth(s *model.McpServer) {
+	ctx, cancel := context.WithTimeout(withoutKeepAlive(context.Background()), healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := m.callMcpServer(ctx, s, false, func(ctx context.Context, c *client.Client) error {
+		return c.Ping(ctx)
+	})
+	if err != nil && ctx.Err() != nil {
+		err = fmt.Errorf("MCP server did not respond to ping within %s", healthCheckTimeout)
+	}
+	m.health.record(s.Name, start, time.Since(start), err)
+}
+
+// GetMcpServerHealth returns the latest health status of a registered MCP server.
+func (m *MCPService) GetMcpServerHealth(name string) (*model.ServerHealth, error) {
+	s, err := m.GetMcpServer(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP server %s from DB: %w", name, err)
+	}
+	return m.health.get(s.Name), nil
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"log"
+	"sync"
+)
+
+const (
+	// methodNotificationMessage is the method of the notifications carrying log messages.
+	methodNotificationMessage = "notifications/message"
+
+	// upstreamLogLevel is the minimum level of the log messages requested from upstream MCP servers.
+	upstreamLogLevel = mcp.LoggingLevelInfo
+)
+
+// logLevelSeverity orders the log levels defined by MCP (RFC 5424 syslog levels) from the least to the most severe.
+var logLevelSeverity = map[mcp.LoggingLevel]int{
+	mcp.LoggingLevelDebug:     0,
+	mcp.LoggingLevelInfo:      1,
+	mcp.LoggingLevelNotice:    2,
+	mcp.LoggingLevelWarning:   3,
+	mcp.LoggingLevelError:     4,
+	mcp.LoggingLevelCritical:  5,
+	mcp.LoggingLevelAlert:     6,
+	mcp.LoggingLevelEmergency: 7,
+}
+
+// logRelay writes the log messages sent by upstream MCP servers to MCPJungle's logs
+// and relays them to the downstream MCP sessions that asked for log messages with logging/setLevel.
+type logRelay struct {
+	mu sync.RWMutex
+	// subscribers holds the downstream sessions that set a log level, by session ID
+	subscribers map[string]logSubscriber
+
+	// send delivers a log message notification to a downstream session
+	send func(sessionID string, params map[string]any) error
+}
+
+// logSubscriber is a downstream MCP session that asked for log messages.
+type logSubscriber struct {
+	// session keeps the log level the client set, which it may change at any time
+	session server.SessionWithLogging
+	// ctx carries the MCP client that owns the session, only its values are used to check the client's access
+	ctx context.Context
+}
+
+func newLogRelay(send func(sessionID string, params map[string]any) error) *logRelay {
+	return &logRelay{
+		subscribers: make(map[string]logSubscriber),
+		send:        send,
+	}
+}
+
+// subscribe starts relaying upstream log messages to the downstream session that set its log level with ctx,
+// at the level it set and above. ctx identifies the MCP client that sent the request:
+// messages are only relayed from the MCP servers the client is allowed to access.
+func (r *logRelay) subscribe(ctx context.Context) {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithLogging)
+	if !ok || session.SessionID() == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers[session.SessionID()] = logSubscriber{session: session, ctx: context.WithoutCancel(ctx)}
+}
+
+// forget stops relaying log messages to a downstream session.
+func (r *logRelay) forget(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subscribers, sessionID)
+}
+
+// relay handles a log message sent by an upstream MCP server.
+// If session is not empty, the message was sent on a connection dedicated to that downstream session,
+// so it is only relayed to that session. Otherwise, it is relayed to all the sessions allowed to access the server.
+func (r *logRelay) relay(serverName, session string, n mcp.JSONRPCNotification) {
+	level, _ := n.Params.AdditionalFields["level"].(string)
+	logger, _ := n.Params.AdditionalFields["logger"].(string)
+	data := n.Params.AdditionalFields["data"]
+
+	if logger == "" {
+		log.Printf("[upstream:%s] %s: %s", serverName, level, formatLogData(data))
+	} else {
+		log.Printf("[upstream:%s] %s: %s: %s", serverName, level, logger, formatLogData(data))
+	}
+
+	severity, ok := logLevelSeverity[mcp.LoggingLevel(level)]
+	if !ok {
+		return
+	}
+	// the name of the server is prepended to the logger, so that clients know where the message comes from
+	params := map[string]any{"level": level, "logger": serverName, "data": data}
+	if logger != "" {
+		params["logger"] = joinServerName(serverName, logger)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for id, sub := range r.subscribers {
+		if session != "" && id != session {
+			continue
+		}
+		if severity < logLevelSeverity[sub.session.GetLogLevel()] || checkClientServerAccess(sub.ctx, serverName) != nil {
+			continue
+		}
+		// the session may not have a stream open to receive notifications, the message is dropped then
+		_ = r.send(id, params)
+	}
+}
+
+// formatLogData turns the data of a log message, which may be any JSON value, into a single line.
+func formatLogData(data any) string {
+	if s, ok := data.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Sprint(data)
+	}
+	return string(b)
+}
+
+// enableUpstreamLogging asks an upstream MCP server that supports logging to send log messages
+// at upstreamLogLevel and above. Failing to do so doesn't prevent the use of the server.
+func enableUpstreamLogging(ctx context.Context, c *client.Client, info *mcp.InitializeResult, serverName string) {
+	if info.Capabilities.Logging == nil {
+		return
+	}
+	req := mcp.SetLevelRequest{}
+	req.Params.Level = upstreamLogLevel
+	if err := c.SetLevel(ctx, req); err != nil {
+		log.Printf("[upstream:%s] failed to set log level: %v", serverName, err)
+	}
+}
+
+// sendLogMessage delivers a log message notification to a downstream session of the MCP proxy server.
+func sendLogMessage(s *server.MCPServer) func(sessionID string, params map[string]any) error {
+	return func(sessionID string, params map[string]any) error {
+		return s.SendNotificationToSpecificClient(sessionID, methodNotificationMessage, params)
+	}
+}
@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"context"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testLoggingSession is a downstream session that keeps its log level.
+type testLoggingSession struct {
+	id    string
+	level mcp.LoggingLevel
+}
+
+func (s *testLoggingSession) Initialize()                                         {}
+func (s *testLoggingSession) Initialized() bool                                   { return true }
+func (s *testLoggingSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s *testLoggingSession) SessionID() string                                   { return s.id }
+func (s *testLoggingSession) SetLogLevel(level mcp.LoggingLevel)                  { s.level = level }
+func (s *testLoggingSession) GetLogLevel() mcp.LoggingLevel                       { return s.level }
+
+// withLoggingSession returns a context carrying a downstream session that set the given log level.
+func withLoggingSession(ctx context.Context, id string, level mcp.LoggingLevel) context.Context {
+	return server.NewMCPServer("test", "0.0.0").WithContext(ctx, &testLoggingSession{id: id, level: level})
+}
+
+func TestLogRelay(t *testing.T) {
+	message := func(level mcp.LoggingLevel, logger string) mcp.JSONRPCNotification {
+		fields := map[string]any{"level": string(level), "data": "hello"}
+		if logger != "" {
+			fields["logger"] = logger
+		}
+		return mcp.JSONRPCNotification{
+			Notification: mcp.Notification{
+				Method: methodNotificationMessage,
+				Params: mcp.NotificationParams{AdditionalFields: fields},
+			},
+		}
+	}
+
+	// in production mode, the session's client may only access the "time" server
+	prodCtx := context.WithValue(context.Background(), "mode", model.ModeProd)
+	prodCtx = context.WithValue(prodCtx, "client", &model.McpClient{Name: "c", AllowList: []byte(`["time"]`)})
+
+	tests := []struct {
+		name    string
+		server  string
+		session string
+		n       mcp.JSONRPCNotification
+		want    []string
+	}{
+		{
+			name:   "relays messages at or above the session level",
+			server: "time",
+			n:      message(mcp.LoggingLevelWarning, ""),
+			want:   []string{"debug", "warning"},
+		},
+		{
+			name:   "drops messages below the session level",
+			server: "time",
+			n:      message(mcp.LoggingLevelInfo, ""),
+			want:   []string{"debug"},
+		},
+		{
+			name:   "only relays to sessions allowed to access the server",
+			server: "secret",
+			n:      message(mcp.LoggingLevelError, ""),
+			want:   []string{"debug"},
+		},
+		{
+			name:    "messages on a dedicated connection only go to its session",
+			server:  "time",
+			session: "warning",
+			n:       message(mcp.LoggingLevelError, ""),
+			want:    []string{"warning"},
+		},
+		{
+			name:   "drops messages with an unknown level",
+			server: "time",
+			n:      message("verbose", ""),
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			r := newLogRelay(func(sessionID string, params map[string]any) error {
+				got = append(got, sessionID)
+				return nil
+			})
+			r.subscribe(withLoggingSession(context.Background(), "debug", mcp.LoggingLevelDebug))
+			r.subscribe(withLoggingSession(prodCtx, "warning", mcp.LoggingLevelWarning))
+
+			r.relay(tt.server, tt.session, tt.n)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("relayed to %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("prefixes the logger with the server name", func(t *testing.T) {
+		var got []any
+		r := newLogRelay(func(_ string, params map[string]any) error {
+			got = append(got, params["logger"])
+			return nil
+		})
+		r.subscribe(withLoggingSession(context.Background(), "s", mcp.LoggingLevelDebug))
+
+		r.relay("time", "", message(mcp.LoggingLevelInfo, ""))
+		r.relay("time", "", message(mcp.LoggingLevelInfo, "clock"))
+		want := []any{"time", joinServerName("time", "clock")}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("loggers = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestSetLevelSubscribesSession(t *testing.T) {
+	m := newTestMCPService(t)
+	srv := server.NewTestStreamableHTTPServer(m.mcpProxyServer)
+	t.Cleanup(srv.Close)
+
+	c, err := client.NewStreamableHttpClient(srv.URL + "/mcp")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	ctx := context.Background()
+	if _, err := c.Initialize(ctx, mcp.InitializeRequest{}); err != nil {
+		t.Fatalf("failed to initialize client: %v", err)
+	}
+	sessionID := c.GetSessionId()
+
+	for _, level := range []mcp.LoggingLevel{mcp.LoggingLevelDebug, mcp.LoggingLevelError} {
+		req := mcp.SetLevelRequest{}
+		req.Params.Level = level
+		if err := c.SetLevel(ctx, req); err != nil {
+			t.Fatalf("SetLevel(%s) error = %v", level, err)
+		}
+		m.logs.mu.RLock()
+		sub, ok := m.logs.subscribers[sessionID]
+		m.logs.mu.RUnlock()
+		if !ok {
+			t.Fatalf("session %s was not subscribed to log messages", sessionID)
+		}
+		if got := sub.session.GetLogLevel(); got != level {
+			t.Errorf("log level of the session = %s, want %s", got, level)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package mcp
 
 import (
+	"errors"
 	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
 	"gorm.io/gorm"
 )
 
@@ -11,17 +14,156 @@ import (
 type MCPService struct {
 	db             *gorm.DB
 	mcpProxyServer *server.MCPServer
+
+	// stdioServers supervises the processes of all registered stdio MCP servers
+	stdioServers *stdioSupervisor
+	// conns holds the persistent connections to HTTP-based upstream MCP servers
+	conns *connPool
+	// breakers holds the circuit breakers guarding calls to upstream MCP servers
+	breakers *breakerSet
+	// health holds the outcome of the background health checks of upstream MCP servers
+	health *healthChecker
+	// progress routes the progress notifications of upstream calls back to the downstream sessions that made them
+	progress *progressRelay
+	// logs writes the log messages of upstream MCP servers to MCPJungle's logs and relays them to downstream sessions
+	logs *logRelay
+	// calls holds the tool calls being handled by the MCP proxy server, so that they can be cancelled
+	calls *inflightCalls
+	// clientSessions holds the downstream sessions of each MCP client, so that they can be notified of access changes
+	clientSessions *clientSessionSet
+	// downstreamSessions holds the MCP endpoint each downstream session was initialized on, the capabilities
+	// of its client and the stream on which the client receives requests
+	downstreamSessions *downstreamSessionSet
+	// watchers keep the connections on which upstream MCP servers notify changes to their tools, resources and prompts
+	watchers *toolWatcherSet
+
+	// syncLocks serializes the syncs of the tools of each MCP server, syncs of different servers run concurrently
+	syncLocks *serverLockSet
+
+	// allowStdio is true if MCP servers using the stdio transport can be registered or updated.
+	// Since MCPJungle runs their command on its host, this must be enabled explicitly.
+	allowStdio bool
 }
 
 // NewMCPService creates a new instance of MCPService.
 // It initializes the MCP proxy server by loading all registered tools from the database.
+// It also starts the processes of all registered stdio MCP servers, the health checks and the watchers
+// of upstream tool changes in the background.
 func NewMCPService(db *gorm.DB, mcpProxyServer *server.MCPServer) (*MCPService, error) {
 	s := &MCPService{
-		db:             db,
-		mcpProxyServer: mcpProxyServer,
+		db:                 db,
+		mcpProxyServer:     mcpProxyServer,
+		breakers:           newBreakerSet(),
+		syncLocks:          newServerLockSet(),
+		health:             newHealthChecker(),
+		progress:           newProgressRelay(),
+		calls:              newInflightCalls(),
+		logs:               newLogRelay(sendLogMessage(mcpProxyServer)),
+		clientSessions:     newClientSessionSet(),
+		downstreamSessions: newDownstreamSessionSet(),
 	}
+	s.watchers = newToolWatcherSet(s.syncNotifiedServerTools)
+	s.conns = newConnPool(s.handleUpstreamNotification, s.newUpstreamRequestRelay)
+	s.stdioServers = newStdioSupervisor(s.handleUpstreamNotification)
 	if err := s.initMCPProxyServer(); err != nil {
 		return nil, fmt.Errorf("failed to initialize MCP proxy server: %w", err)
 	}
+	if err := s.launchStdioServers(); err != nil {
+		return nil, fmt.Errorf("failed to start stdio MCP servers: %w", err)
+	}
+	if err := s.watchServers(); err != nil {
+		return nil, fmt.Errorf("failed to watch MCP servers for tool changes: %w", err)
+	}
+	go s.runHealthChecks()
 	return s, nil
 }
+
+// AllowStdioServers lets MCP servers using the stdio transport be registered and updated.
+// Anyone who can register such a server can run commands on MCPJungle's host, so this is disabled by default.
+// It must be called before the registry starts serving requests.
+func (m *MCPService) AllowStdioServers() {
+	m.allowStdio = true
+}
+
+// ErrStdioNotAllowed is returned when registering or updating an MCP server using the stdio transport
+// while such servers are not allowed.
+var ErrStdioNotAllowed = errors.New(
+	"MCP servers using the stdio transport are disabled, start the registry with --allow-stdio to enable them",
+)
+
+// checkStdioAllowed returns ErrStdioNotAllowed if the server uses the stdio transport but stdio servers are not allowed.
+func (m *MCPService) checkStdioAllowed(s *model.McpServer) error {
+	if s.Transport == model.TransportStdio && !m.allowStdio {
+		return ErrStdioNotAllowed
+	}
+	return nil
+}
+
+// Shutdown stops the health checks, closes all connections to upstream MCP servers and stops
+// all the processes of stdio MCP servers managed by MCPJungle.
+func (m *MCPService) Shutdown() {
+	m.health.stop()
+	m.watchers.stopAll()
+	m.conns.close()
+	m.stdioServers.stopAll()
+}
+
+// launchStdioServers starts supervising the processes of all stdio MCP servers registered in the DB.
+func (m *MCPService) launchStdioServers() error {
+	var servers []model.McpServer
+	if err := m.db.Where("transport = ?", model.TransportStdio).Find(&servers).Error; err != nil {
+		return err
+	}
+	for i := range servers {
+		m.stdioServers.launch(&servers[i])
+	}
+	return nil
+}
+
+// watchServers starts watching all HTTP-based MCP servers registered in the DB for changes to their tools.
+func (m *MCPService) watchServers() error {
+	var servers []model.McpServer
+	if err := m.db.Where("transport <> ?", model.TransportStdio).Find(&servers).Error; err != nil {
+		return err
+	}
+	for i := range servers {
+		m.watchers.start(&servers[i])
+	}
+	return nil
+}
+
+// handleUpstreamNotification handles a notification sent by an upstream MCP server on any of its connections.
+// session is the downstream MCP session the connection is dedicated to, if any.
+func (m *MCPService) handleUpstreamNotification(server, session string, n mcp.JSONRPCNotification) {
+	switch n.Method {
+	case methodNotificationProgress:
+		m.progress.relay(n)
+	case methodNotificationMessage:
+		m.logs.relay(server, session, n)
+	default:
+		m.watchers.handleNotification(server, n)
+	}
+}
+
+// SessionIdManager returns the session ID manager to be used by the streamable HTTP transport of the proxy.
+// It closes the upstream sessions dedicated to a downstream MCP session when the client terminates it.
+func (m *MCPService) SessionIdManager() server.SessionIdManager {
+	return &proxySessionIdManager{conns: m.conns, logs: m.logs, sessions: m.downstreamSessions}
+}
+
+// proxySessionIdManager generates session IDs the same way mcp-go does by default,
+// but also tears down the upstream sessions tied to a downstream session when it is terminated
+// and stops relaying log messages to it.
+type proxySessionIdManager struct {
+	server.InsecureStatefulSessionIdManager
+	conns    *connPool
+	logs     *logRelay
+	sessions *downstreamSessionSet
+}
+
+func (s *proxySessionIdManager) Terminate(sessionID string) (bool, error) {
+	s.conns.drainSession(sessionID)
+	s.logs.forget(sessionID)
+	s.sessions.forget(sessionID)
+	return s.InsecureStatefulSessionIdManager.Terminate(sessionID)
+}
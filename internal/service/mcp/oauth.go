@@ -0,0 +1,237 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// oauthTokenRefreshMargin is how long before its expiry an access token is refreshed.
+	oauthTokenRefreshMargin = time.Minute
+	// oauthTokenRequestTimeout bounds the time the token endpoint gets to issue an access token.
+	oauthTokenRequestTimeout = 30 * time.Second
+)
+
+// oauthTokenSources caches the token sources of all upstream servers using the oauth2 auth type,
+// so that access tokens are shared by all connections made to a server with the same settings.
+// The sources of a server are evicted when it is updated or deregistered.
+var oauthTokenSources = struct {
+	mu      sync.Mutex
+	sources map[oauthSourceKey]*oauthTokenSource
+}{sources: make(map[oauthSourceKey]*oauthTokenSource)}
+
+// oauthSourceKey identifies a cached token source.
+type oauthSourceKey struct {
+	// server is the name of the upstream MCP server
+	server string
+	config oauthClientConfig
+	// tls contains the TLS settings of the server, which are also used to reach the token endpoint
+	tls [4]string
+}
+
+// oauthClientConfig contains the OAuth 2.0 client credentials grant settings of an upstream MCP server.
+type oauthClientConfig struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       string
+}
+
+// getOAuthTokenSource returns the token source for the client credentials of the given server.
+// Requests to the token endpoint are sent through the given transport, which carries the server's TLS settings.
+func getOAuthTokenSource(s *model.McpServer, rt http.RoundTripper) *oauthTokenSource {
+	key := oauthSourceKey{
+		server: s.Name,
+		config: oauthClientConfig{
+			tokenURL:     s.OAuthTokenURL,
+			clientID:     s.OAuthClientID,
+			clientSecret: s.OAuthClientSecret,
+			scopes:       strings.Join(strings.Fields(s.OAuthScopes), " "),
+		},
+		tls: [4]string{s.TLSCACert, s.TLSClientCert, s.TLSClientKey, s.TLSServerName},
+	}
+
+	oauthTokenSources.mu.Lock()
+	defer oauthTokenSources.mu.Unlock()
+	ts, ok := oauthTokenSources.sources[key]
+	if !ok {
+		ts = &oauthTokenSource{
+			config:     key.config,
+			httpClient: &http.Client{Transport: rt, Timeout: oauthTokenRequestTimeout},
+		}
+		oauthTokenSources.sources[key] = ts
+	}
+	return ts
+}
+
+// evictOAuthTokenSources discards the cached token sources of a server, along with their access tokens.
+func evictOAuthTokenSources(server string) {
+	oauthTokenSources.mu.Lock()
+	defer oauthTokenSources.mu.Unlock()
+	for key := range oauthTokenSources.sources {
+		if key.server == server {
+			delete(oauthTokenSources.sources, key)
+		}
+	}
+}
+
+// oauthTokenSource fetches access tokens using the OAuth 2.0 client credentials grant (RFC 6749 section 4.4)
+// and caches them until shortly before they expire.
+type oauthTokenSource struct {
+	config     oauthClientConfig
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// oauthTokenResponse is the successful response of a token endpoint.
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// ExpiresIn is the lifetime of the access token in seconds.
+	// If the token endpoint omits it, the token is used until the upstream server rejects it.
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+// Token returns a valid access token, fetching a new one if there is no cached token or it is about to expire.
+func (ts *oauthTokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != "" && (ts.expiry.IsZero() || time.Now().Add(oauthTokenRefreshMargin).Before(ts.expiry)) {
+		return ts.token, nil
+	}
+	resp, err := ts.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	ts.token = resp.AccessToken
+	ts.expiry = time.Time{}
+	if resp.ExpiresIn > 0 {
+		ts.expiry = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	return ts.token, nil
+}
+
+// Invalidate discards the cached access token if it is the given one,
+// so that the next call to Token fetches a new one.
+func (ts *oauthTokenSource) Invalidate(token string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.token == token {
+		ts.token = ""
+	}
+}
+
+// fetch requests a new access token from the token endpoint.
+func (ts *oauthTokenSource) fetch(ctx context.Context) (*oauthTokenResponse, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if ts.config.scopes != "" {
+		form.Set("scope", ts.config.scopes)
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, ts.config.tokenURL, strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(ts.config.clientID), url.QueryEscape(ts.config.clientSecret))
+
+	resp, err := ts.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request access token from %s: %w", ts.config.tokenURL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"token endpoint %s returned status %d: %s", ts.config.tokenURL, resp.StatusCode, string(body),
+		)
+	}
+
+	var tr oauthTokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tr.AccessToken == "" {
+		return nil, fmt.Errorf("token endpoint %s did not return an access token", ts.config.tokenURL)
+	}
+	if tr.TokenType != "" && !strings.EqualFold(tr.TokenType, "bearer") {
+		return nil, fmt.Errorf("unsupported token type returned by token endpoint: %s", tr.TokenType)
+	}
+	return &tr, nil
+}
+
+// oauthTransport is an http.RoundTripper that authenticates requests with an access token.
+// If the upstream server rejects the token, a new token is fetched and the request is retried once.
+type oauthTransport struct {
+	base   http.RoundTripper
+	source *oauthTokenSource
+}
+
+func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(withBearerToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// the token was revoked or expired earlier than advertised
+	retry, err := rewindRequest(req)
+	if err != nil {
+		// the request cannot be sent again, let the caller deal with the 401
+		return resp, nil
+	}
+	t.source.Invalidate(token)
+	token, err = t.source.Token(req.Context())
+	if err != nil {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return t.base.RoundTrip(withBearerToken(retry, token))
+}
+
+// withBearerToken returns a copy of the request carrying the given access token.
+// A RoundTripper must not modify the original request.
+func withBearerToken(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+// rewindRequest returns a copy of the request with a fresh body so that it can be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return r, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("request body cannot be rewound")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = body
+	return r, nil
+}
@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newFakeTokenEndpoint starts a token endpoint that issues tokens named token-1, token-2, ...
+// with the given lifetime, and only to the client "id" with secret "secret".
+func newFakeTokenEndpoint(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	var issued atomic.Int32
+	srv := httptest.NewServer(fakeTokenHandler(expiresIn, &issued))
+	t.Cleanup(srv.Close)
+	return srv, &issued
+}
+
+func fakeTokenHandler(expiresIn int, issued *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "id" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "invalid_client"}`))
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("scope") != "read write" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n := issued.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   expiresIn,
+		})
+	})
+}
+
+func newTestTokenSource(tokenURL, secret string) *oauthTokenSource {
+	return &oauthTokenSource{
+		config:     oauthClientConfig{tokenURL: tokenURL, clientID: "id", clientSecret: secret, scopes: "read write"},
+		httpClient: http.DefaultClient,
+	}
+}
+
+func TestOAuthTokenSource(t *testing.T) {
+	t.Run("caches token", func(t *testing.T) {
+		srv, issued := newFakeTokenEndpoint(t, 3600)
+		ts := newTestTokenSource(srv.URL, "secret")
+		for i := 0; i < 3; i++ {
+			tok, err := ts.Token(context.Background())
+			if err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+			if tok != "token-1" {
+				t.Errorf("Token() = %q, want %q", tok, "token-1")
+			}
+		}
+		if issued.Load() != 1 {
+			t.Errorf("token endpoint called %d times, want 1", issued.Load())
+		}
+	})
+
+	t.Run("refreshes token before expiry", func(t *testing.T) {
+		// a token which expires within the refresh margin is never reused
+		srv, _ := newFakeTokenEndpoint(t, 10)
+		ts := newTestTokenSource(srv.URL, "secret")
+		first, _ := ts.Token(context.Background())
+		second, err := ts.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+		if first == second {
+			t.Errorf("Token() returned the same token %q twice, want a refreshed token", first)
+		}
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		srv, _ := newFakeTokenEndpoint(t, 3600)
+		ts := newTestTokenSource(srv.URL, "wrong")
+		if _, err := ts.Token(context.Background()); err == nil {
+			t.Error("Token() error = nil, want error")
+		}
+	})
+}
+
+func TestOAuthTransportRetriesOnUnauthorized(t *testing.T) {
+	tokenSrv, issued := newFakeTokenEndpoint(t, 3600)
+
+	// the upstream only accepts the second token, as if the first one had been revoked
+	var bodies []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	c := &http.Client{Transport: &oauthTransport{
+		base:   http.DefaultTransport,
+		source: newTestTokenSource(tokenSrv.URL, "secret"),
+	}}
+	resp, err := c.Post(upstream.URL, "application/json", strings.NewReader(`{"jsonrpc": "2.0"}`))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if issued.Load() != 2 {
+		t.Errorf("token endpoint called %d times, want 2", issued.Load())
+	}
+	if len(bodies) != 2 || bodies[1] != `{"jsonrpc": "2.0"}` {
+		t.Errorf("upstream received bodies %q, want the request to be sent twice", bodies)
+	}
+}
+
+func TestOAuthTokenSourceUsesUpstreamTLS(t *testing.T) {
+	var issued atomic.Int32
+	tokenSrv := httptest.NewTLSServer(fakeTokenHandler(3600, &issued))
+	t.Cleanup(tokenSrv.Close)
+
+	s := &model.McpServer{
+		Name:              "secure",
+		AuthType:          model.AuthTypeOAuth2,
+		OAuthTokenURL:     tokenSrv.URL,
+		OAuthClientID:     "id",
+		OAuthClientSecret: "secret",
+		OAuthScopes:       "read write",
+		TLSCACert: string(pem.EncodeToMemory(
+			&pem.Block{Type: "CERTIFICATE", Bytes: tokenSrv.Certificate().Raw},
+		)),
+	}
+	t.Cleanup(func() { evictOAuthTokenSources(s.Name) })
+	c, err := newUpstreamHTTPClient(s)
+	if err != nil {
+		t.Fatalf("newUpstreamHTTPClient() error = %v", err)
+	}
+	ts := c.Transport.(*oauthTransport).source
+	// the token endpoint's certificate is only trusted through the server's CA bundle
+	if _, err := ts.Token(context.Background()); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+
+	if getOAuthTokenSource(s, nil) != ts {
+		t.Error("getOAuthTokenSource() returned a new source, want the cached one")
+	}
+	evictOAuthTokenSources(s.Name)
+	if getOAuthTokenSource(s, nil) == ts {
+		t.Error("getOAuthTokenSource() returned the evicted source")
+	}
+}
@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/types"
+	"gorm.io/datatypes"
+	"strings"
+)
+
+// UpdateToolOverrides changes how a tool is exposed, eg- its name or description, and returns its new definition.
+// The tool is replaced in the MCP proxy server right away.
+func (m *MCPService) UpdateToolOverrides(name string, u *types.ToolOverridesUpdate) (*model.Tool, error) {
+	serverName, toolName, err := m.resolveToolName(name)
+	if err != nil {
+		return nil, err
+	}
+	s, err := m.GetMcpServer(serverName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP server %s from DB: %w", serverName, err)
+	}
+	var tool model.Tool
+	if err := m.db.Where("server_id = ? AND name = ?", s.ID, toolName).First(&tool).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tool %s from DB: %w", name, err)
+	}
+
+	overrides := tool.Overrides
+	if u.Alias != nil {
+		overrides.Alias = strings.TrimSpace(*u.Alias)
+		if err := m.validateToolAlias(s, &tool, overrides.Alias); err != nil {
+			return nil, err
+		}
+	}
+	if u.Title != nil {
+		overrides.Title = *u.Title
+	}
+	if u.Description != nil {
+		overrides.Description = *u.Description
+	}
+	if len(u.ParameterDescriptions) > 0 {
+		descriptions, err := mergeParameterDescriptions(&tool, u.ParameterDescriptions)
+		if err != nil {
+			return nil, err
+		}
+		overrides.ParameterDescriptions = descriptions
+	}
+
+	oldName := exposedToolName(s.Name, &tool)
+	err = m.db.Model(&tool).Updates(map[string]any{
+		"override_alias":                  overrides.Alias,
+		"override_title":                  overrides.Title,
+		"override_description":            overrides.Description,
+		"override_parameter_descriptions": overrides.ParameterDescriptions,
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to update overrides of tool %s: %w", name, err)
+	}
+	tool.Overrides = overrides
+
+	if newName := exposedToolName(s.Name, &tool); newName != oldName {
+		m.mcpProxyServer.DeleteTools(oldName)
+	}
+	if err := m.addProxyTool(s, &tool); err != nil {
+		return nil, err
+	}
+	return applyToolOverrides(s.Name, &tool)
+}
+
+// validateToolAlias checks that a tool of an MCP server can be exposed with the given alias,
+// ie- the alias is a valid tool name and no other tool of the server is already exposed with that name.
+func (m *MCPService) validateToolAlias(s *model.McpServer, t *model.Tool, alias string) error {
+	if alias == "" {
+		return nil
+	}
+	if err := validateToolAliasName(alias); err != nil {
+		return err
+	}
+	var count int64
+	err := m.db.Model(&model.Tool{}).
+		Where("server_id = ? AND id <> ? AND (name = ? OR override_alias = ?)", s.ID, t.ID, alias, alias).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("failed to check alias %s of tool %s: %w", alias, t.Name, err)
+	}
+	if count > 0 {
+		return fmt.Errorf("invalid alias: MCP server %s already has a tool named %s", s.Name, alias)
+	}
+	return nil
+}
+
+// mergeParameterDescriptions applies changes to the overridden descriptions of a tool's input parameters.
+// An empty description removes the override of the parameter.
+func mergeParameterDescriptions(t *model.Tool, changes map[string]string) (datatypes.JSON, error) {
+	descriptions := make(map[string]string)
+	if len(t.Overrides.ParameterDescriptions) > 0 {
+		if err := json.Unmarshal(t.Overrides.ParameterDescriptions, &descriptions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal parameter descriptions of tool %s: %w", t.Name, err)
+		}
+	}
+	properties, err := inputSchemaProperties(t.InputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the input schema of tool %s: %w", t.Name, err)
+	}
+	for param, description := range changes {
+		if description == "" {
+			delete(descriptions, param)
+			continue
+		}
+		if _, ok := properties[param]; !ok {
+			return nil, fmt.Errorf("invalid input: tool %s has no parameter named %s", t.Name, param)
+		}
+		descriptions[param] = description
+	}
+	if len(descriptions) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(descriptions)
+}
+
+// inputSchemaProperties returns the schemas of the input parameters of a tool, by parameter name.
+func inputSchemaProperties(schema datatypes.JSON) (map[string]any, error) {
+	if len(schema) == 0 || string(schema) == "null" {
+		return map[string]any{}, nil
+	}
+	var s struct {
+		Properties map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, err
+	}
+	return s.Properties, nil
+}
+
+// exposedToolName returns the name a tool of the given MCP server is exposed with, which is its alias if it has one.
+func exposedToolName(serverName string, t *model.Tool) string {
+	name := t.Name
+	if t.Overrides.Alias != "" {
+		name = t.Overrides.Alias
+	}
+	return mergeServerToolNames(serverName, name)
+}
+
+// applyToolOverrides returns the definition of a tool of the given MCP server as it is exposed,
+// ie- with its canonical name and with its overrides in place of the definition provided by the server.
+func applyToolOverrides(serverName string, t *model.Tool) (*model.Tool, error) {
+	exposed := *t
+	exposed.Name = exposedToolName(serverName, t)
+	if t.Overrides.Title != "" {
+		exposed.Title = t.Overrides.Title
+		// the title may also have been provided as an annotation, which takes precedence in the MCP proxy server
+		var annotations map[string]any
+		if json.Unmarshal(t.Annotations, &annotations) == nil && annotations["title"] != nil {
+			annotations["title"] = t.Overrides.Title
+			if a, err := json.Marshal(annotations); err == nil {
+				exposed.Annotations = a
+			}
+		}
+	}
+	if t.Overrides.Description != "" {
+		exposed.Description = t.Overrides.Description
+	}
+	if len(t.Overrides.ParameterDescriptions) == 0 {
+		return &exposed, nil
+	}
+
+	var descriptions map[string]string
+	if err := json.Unmarshal(t.Overrides.ParameterDescriptions, &descriptions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal parameter descriptions of tool %s: %w", exposed.Name, err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(t.InputSchema, &schema); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal input schema of tool %s: %w", exposed.Name, err)
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	for param, description := range descriptions {
+		// the MCP server may have removed the parameter since its description was overridden
+		if p, ok := properties[param].(map[string]any); ok {
+			p["description"] = description
+		}
+	}
+	inputSchema, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal input schema of tool %s: %w", exposed.Name, err)
+	}
+	exposed.InputSchema = inputSchema
+	return &exposed, nil
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"encoding/json"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestApplyToolOverrides(t *testing.T) {
+	tool := &model.Tool{
+		Name:        "search",
+		Title:       "Search",
+		Description: "search",
+		InputSchema: []byte(`{"type":"object","properties":{"q":{"type":"string","description":"query"},"n":{"type":"integer"}}}`),
+		Annotations: []byte(`{"title":"Search","readOnlyHint":true}`),
+	}
+
+	exposed, err := applyToolOverrides("github", tool)
+	if err != nil {
+		t.Fatalf("applyToolOverrides() error = %v", err)
+	}
+	if exposed.Name != "github/search" || exposed.Description != "search" {
+		t.Errorf("applyToolOverrides() without overrides = (%q, %q), want (%q, %q)",
+			exposed.Name, exposed.Description, "github/search", "search")
+	}
+
+	tool.Overrides = model.ToolOverrides{
+		Alias:       "search_issues",
+		Title:       "Search issues",
+		Description: "Search the issues of GitHub repositories",
+		// "gone" was removed from the input schema after its description was overridden
+		ParameterDescriptions: []byte(`{"q":"Search terms, eg- is:open label:bug","gone":"ignored"}`),
+	}
+	exposed, err = applyToolOverrides("github", tool)
+	if err != nil {
+		t.Fatalf("applyToolOverrides() error = %v", err)
+	}
+	if exposed.Name != "github/search_issues" {
+		t.Errorf("Name = %q, want %q", exposed.Name, "github/search_issues")
+	}
+	if exposed.Title != "Search issues" || exposed.Description != "Search the issues of GitHub repositories" {
+		t.Errorf("(Title, Description) = (%q, %q), want the overrides", exposed.Title, exposed.Description)
+	}
+	if !strings.Contains(string(exposed.Annotations), `"title":"Search issues"`) {
+		t.Errorf("Annotations = %s, want the overridden title", exposed.Annotations)
+	}
+
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal(exposed.InputSchema, &schema); err != nil {
+		t.Fatalf("failed to unmarshal input schema: %v", err)
+	}
+	if got := schema.Properties["q"]["description"]; got != "Search terms, eg- is:open label:bug" {
+		t.Errorf("description of q = %v, want the override", got)
+	}
+	if _, ok := schema.Properties["n"]["description"]; ok {
+		t.Error("description of n was set, want it left untouched")
+	}
+	if _, ok := schema.Properties["gone"]; ok {
+		t.Error("parameter gone was added to the input schema")
+	}
+	if tool.Name != "search" || strings.Contains(string(tool.InputSchema), "Search terms") {
+		t.Error("applyToolOverrides() modified the stored tool")
+	}
+}
+
+func TestMergeParameterDescriptions(t *testing.T) {
+	tool := &model.Tool{
+		Name:        "search",
+		InputSchema: []byte(`{"type":"object","properties":{"q":{"type":"string"},"n":{"type":"integer"}}}`),
+		Overrides:   model.ToolOverrides{ParameterDescriptions: []byte(`{"n":"max results"}`)},
+	}
+
+	got, err := mergeParameterDescriptions(tool, map[string]string{"q": "query"})
+	if err != nil {
+		t.Fatalf("mergeParameterDescriptions() error = %v", err)
+	}
+	if !jsonEqual(got, []byte(`{"n":"max results","q":"query"}`)) {
+		t.Errorf("mergeParameterDescriptions() = %s, want both descriptions", got)
+	}
+
+	got, err = mergeParameterDescriptions(tool, map[string]string{"n": ""})
+	if err != nil || got != nil {
+		t.Errorf("mergeParameterDescriptions() = (%s, %v), want (nil, nil) once the only override is removed", got, err)
+	}
+
+	if _, err := mergeParameterDescriptions(tool, map[string]string{"missing": "x"}); err == nil {
+		t.Error("mergeParameterDescriptions() accepted a parameter that the tool doesn't have")
+	}
+}
+
+func TestValidateToolAliasName(t *testing.T) {
+	defer func(f toolNameFormat) { toolNames = f }(toolNames)
+
+	tests := []struct {
+		name      string
+		separator string
+		alias     string
+		wantErr   bool
+	}{
+		{name: "valid alias", separator: "/", alias: "search_issues", wantErr: false},
+		{name: "dots and hyphens", separator: "/", alias: "search-issues.v2", wantErr: false},
+		{name: "default separator", separator: "/", alias: "issues/search", wantErr: true},
+		{name: "configured separator", separator: "__", alias: "search__issues", wantErr: true},
+		{name: "slash with another separator configured", separator: "__", alias: "issues/search", wantErr: true},
+		{name: "whitespace", separator: "/", alias: "search issues", wantErr: true},
+		{name: "trailing newline", separator: "/", alias: "search\n", wantErr: true},
+		{name: "special characters", separator: "/", alias: "search:issues", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ConfigureToolNames(tt.separator, 0); err != nil {
+				t.Fatalf("ConfigureToolNames() error = %v", err)
+			}
+			if err := validateToolAliasName(tt.alias); (err != nil) != tt.wantErr {
+				t.Errorf("validateToolAliasName(%q) error = %v, wantErr %v", tt.alias, err, tt.wantErr)
+			}
+		})
+	}
+}
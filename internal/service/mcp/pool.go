@@ -0,0 +1,283 @@
+package mcp
+
+import (
+	"context"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// poolHealthCheckInterval is how often the idle connections in the pool are pinged.
+	poolHealthCheckInterval = 30 * time.Second
+	// poolHealthCheckTimeout bounds the time an upstream MCP server gets to respond to a ping.
+	poolHealthCheckTimeout = 5 * time.Second
+	// poolIdleTimeout is how long a connection may stay unused before it is closed.
+	poolIdleTimeout = 10 * time.Minute
+)
+
+// connPool keeps initialized MCP clients to upstream HTTP-based MCP servers,
+// so that tool calls don't need to go through the initialization handshake every time.
+// Calls that don't belong to a downstream MCP session share a single client per server
+// (mcp-go clients support concurrent requests).
+// Calls made within a downstream MCP session get their own upstream session per server,
+// so that stateful MCP servers retain their state between calls from the same client,
+// and so that the requests the servers send to their client (eg- sampling) can be relayed to the downstream client.
+// Connections that fail their health checks or stay idle for too long are closed.
+type connPool struct {
+	mu    sync.Mutex
+	conns map[poolKey]*pooledConn
+
+	// onNotification receives the notifications sent by upstream servers on pooled connections
+	// along with the downstream session the connection is dedicated to, if any
+	onNotification func(server, session string, n mcp.JSONRPCNotification)
+	// newRelay returns the relay of the requests an upstream server sends on a connection dedicated to
+	// a downstream session, nil if they can't be relayed
+	newRelay func(server, session string) *upstreamRequestRelay
+
+	done chan struct{}
+}
+
+// poolKey identifies a pooled connection.
+type poolKey struct {
+	// server is the name of the upstream MCP server
+	server string
+	// session is the ID of the downstream MCP session the connection is dedicated to.
+	// It is empty for the connection shared by all calls that are not part of a session.
+	session string
+}
+
+// pooledConn is a connection to a single upstream MCP server.
+type pooledConn struct {
+	// mu serializes the creation of the client so that concurrent callers don't race to connect
+	mu       sync.Mutex
+	server   model.McpServer
+	client   *client.Client
+	lastUsed time.Time
+}
+
+func newConnPool(
+	onNotification func(server, session string, n mcp.JSONRPCNotification),
+	newRelay func(server, session string) *upstreamRequestRelay,
+) *connPool {
+	p := &connPool{
+		conns:          make(map[poolKey]*pooledConn),
+		onNotification: onNotification,
+		newRelay:       newRelay,
+		done:           make(chan struct{}),
+	}
+	go p.runHealthChecks()
+	return p
+}
+
+// acquire returns an initialized client for the given server and downstream session,
+// connecting to the server if necessary.
+// If the server's settings have changed since the pooled connection was created, it is replaced.
+func (p *connPool) acquire(ctx context.Context, s *model.McpServer, session string) (*client.Client, error) {
+	key := poolKey{server: s.Name, session: session}
+
+	p.mu.Lock()
+	pc, ok := p.conns[key]
+	if !ok || !pc.server.UpdatedAt.Equal(s.UpdatedAt) {
+		if ok {
+			go pc.close()
+		}
+		pc = &pooledConn{server: *s}
+		p.conns[key] = pc
+	}
+	p.mu.Unlock()
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	created := pc.client == nil
+	if created {
+		var relay *upstreamRequestRelay
+		if p.newRelay != nil && session != "" {
+			relay = p.newRelay(pc.server.Name, session)
+		}
+		c, info, err := createMcpServerConn(ctx, &pc.server, relay, false)
+		if err != nil {
+			return nil, err
+		}
+		if p.onNotification != nil {
+			name := pc.server.Name
+			c.OnNotification(func(n mcp.JSONRPCNotification) {
+				p.onNotification(name, session, n)
+			})
+		}
+		enableUpstreamLogging(ctx, c, info, pc.server.Name)
+		pc.client = c
+	}
+	if created || ctx.Value(keepIdleKey{}) == nil {
+		pc.lastUsed = time.Now()
+	}
+	return pc.client, nil
+}
+
+// keepIdleKey is the context key that marks calls which must not keep a pooled connection from idling out.
+type keepIdleKey struct{}
+
+// withoutKeepAlive returns a context for background calls, such as health checks, which don't count as use
+// of the pooled connection they are made on. Otherwise they would keep connections that no one else uses open forever.
+func withoutKeepAlive(ctx context.Context) context.Context {
+	return context.WithValue(ctx, keepIdleKey{}, true)
+}
+
+// evict closes the pooled connection to a server if it still uses the given client,
+// so that the next caller establishes a fresh connection.
+func (p *connPool) evict(s *model.McpServer, session string, c *client.Client) {
+	p.mu.Lock()
+	pc, ok := p.conns[poolKey{server: s.Name, session: session}]
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if pc.client == c {
+		_ = pc.client.Close()
+		pc.client = nil
+	}
+}
+
+// drain closes all connections to a server and removes them from the pool.
+func (p *connPool) drain(name string) {
+	p.remove(func(k poolKey) bool { return k.server == name })
+}
+
+// drainSession closes all upstream connections dedicated to a downstream MCP session.
+func (p *connPool) drainSession(session string) {
+	if session == "" {
+		return
+	}
+	p.remove(func(k poolKey) bool { return k.session == session })
+}
+
+func (p *connPool) remove(match func(k poolKey) bool) {
+	var removed []*pooledConn
+	p.mu.Lock()
+	for k, pc := range p.conns {
+		if match(k) {
+			removed = append(removed, pc)
+			delete(p.conns, k)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, pc := range removed {
+		pc.close()
+	}
+}
+
+// close drains all connections and stops the health checks.
+func (p *connPool) close() {
+	close(p.done)
+
+	p.mu.Lock()
+	conns := p.conns
+	p.conns = make(map[poolKey]*pooledConn)
+	p.mu.Unlock()
+
+	for _, pc := range conns {
+		pc.close()
+	}
+}
+
+func (pc *pooledConn) close() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if pc.client != nil {
+		_ = pc.client.Close()
+		pc.client = nil
+	}
+}
+
+// runHealthChecks periodically pings all pooled connections and closes the ones that
+// are unresponsive or have been idle for too long.
+func (p *connPool) runHealthChecks() {
+	ticker := time.NewTicker(poolHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+
+		p.mu.Lock()
+		conns := make(map[poolKey]*pooledConn, len(p.conns))
+		for k, pc := range p.conns {
+			conns[k] = pc
+		}
+		p.mu.Unlock()
+
+		for k, pc := range conns {
+			if pc.isIdle() {
+				// a downstream session that stays idle for too long is considered to have ended
+				p.mu.Lock()
+				if p.conns[k] == pc {
+					delete(p.conns, k)
+				}
+				p.mu.Unlock()
+				pc.close()
+				continue
+			}
+			pc.checkHealth()
+		}
+	}
+}
+
+func (pc *pooledConn) isIdle() bool {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	return time.Since(pc.lastUsed) > poolIdleTimeout
+}
+
+func (pc *pooledConn) checkHealth() {
+	// the lock is not held during the ping, so that a slow server doesn't block the callers of acquire
+	pc.mu.Lock()
+	c := pc.client
+	pc.mu.Unlock()
+	if c == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), poolHealthCheckTimeout)
+	defer cancel()
+	err := c.Ping(ctx)
+	if err == nil {
+		return
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	// the client may have been closed or replaced while it was being pinged
+	if pc.client != c {
+		return
+	}
+	log.Printf("[pool] connection to MCP server %s failed health check, closing it: %v", pc.server.Name, err)
+	_ = pc.client.Close()
+	pc.client = nil
+}
+
+// isTransportError returns true if the error was caused by the connection to the upstream MCP server
+// rather than by the server processing the request.
+func isTransportError(err error) bool {
+	return strings.Contains(err.Error(), "transport error")
+}
+
+// isSessionLostError returns true if the upstream MCP server no longer recognizes our session
+// (eg- because it restarted) or the connection was closed before the request could be sent.
+// Such requests never reached the server, so it is safe to retry them on a new connection.
+func isSessionLostError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "session terminated") ||
+		strings.Contains(msg, "Invalid session ID") ||
+		strings.Contains(msg, "transport has been closed") ||
+		strings.Contains(msg, "connection has been closed")
+}
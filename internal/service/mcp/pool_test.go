@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"context"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"testing"
+	"time"
+)
+
+func TestHealthCheckDoesNotKeepConnectionAlive(t *testing.T) {
+	srv := server.NewTestStreamableHTTPServer(server.NewMCPServer("upstream", "0.0.0"))
+	t.Cleanup(srv.Close)
+
+	p := newConnPool(nil, nil)
+	t.Cleanup(p.close)
+	s := &model.McpServer{Name: "upstream", Transport: model.TransportStreamableHTTP, URL: srv.URL + "/mcp"}
+
+	// a connection created by a health check must still idle out
+	ctx := withoutKeepAlive(context.Background())
+	c, err := p.acquire(ctx, s, "")
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	pc := p.conns[poolKey{server: s.Name}]
+	if pc.lastUsed.IsZero() {
+		t.Error("acquire() did not record the use of a new connection")
+	}
+
+	idleSince := time.Now().Add(-poolIdleTimeout - time.Minute)
+	pc.lastUsed = idleSince
+	if _, err := p.acquire(ctx, s, ""); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if !pc.lastUsed.Equal(idleSince) || !pc.isIdle() {
+		t.Error("a health check kept the connection from idling out")
+	}
+
+	if _, err := p.acquire(context.Background(), s, ""); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	if pc.isIdle() {
+		t.Error("a call did not keep the connection from idling out")
+	}
+}
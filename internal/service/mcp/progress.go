@@ -0,0 +1,240 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// methodNotificationProgress is the method of the notifications reporting the progress of a request.
+	methodNotificationProgress = "notifications/progress"
+	// methodNotificationCancelled is the method of the notifications cancelling a request.
+	methodNotificationCancelled = "notifications/cancelled"
+
+	// upstreamCancelTimeout bounds the time spent telling an upstream MCP server that a request was cancelled.
+	upstreamCancelTimeout = 5 * time.Second
+)
+
+// progressRelay routes the progress notifications sent by upstream MCP servers back to the downstream
+// MCP sessions that made the calls they belong to.
+// Every relayed call is given its own progress token, unique across all upstream connections,
+// because connections (eg- to stdio servers) are shared by downstream sessions whose tokens may collide.
+type progressRelay struct {
+	mu    sync.Mutex
+	next  uint64
+	calls map[string]progressTarget
+}
+
+// progressTarget is the downstream call that progress notifications carrying a given upstream token belong to.
+type progressTarget struct {
+	// ctx is the context of the downstream request, which identifies its session
+	ctx context.Context
+	// token is the progress token sent by the downstream client
+	token mcp.ProgressToken
+}
+
+func newProgressRelay() *progressRelay {
+	return &progressRelay{calls: make(map[string]progressTarget)}
+}
+
+// register returns the progress token to send upstream in place of the downstream client's token.
+// release must be called once the call is over.
+func (r *progressRelay) register(ctx context.Context, token mcp.ProgressToken) (string, func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.next++
+	upstreamToken := fmt.Sprintf("mcpjungle-%d", r.next)
+	r.calls[upstreamToken] = progressTarget{ctx: ctx, token: token}
+
+	return upstreamToken, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.calls, upstreamToken)
+	}
+}
+
+// relay forwards a progress notification received from an upstream MCP server to the downstream session
+// of the call it belongs to.
+// Notifications for calls that are already over are dropped.
+func (r *progressRelay) relay(n mcp.JSONRPCNotification) {
+	token, ok := n.Params.AdditionalFields["progressToken"].(string)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	target, ok := r.calls[token]
+	r.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	params := make(map[string]any, len(n.Params.AdditionalFields))
+	for k, v := range n.Params.AdditionalFields {
+		params[k] = v
+	}
+	params["progressToken"] = target.token
+
+	s := server.ServerFromContext(target.ctx)
+	if s == nil {
+		return
+	}
+	if err := s.SendNotificationToClient(target.ctx, methodNotificationProgress, params); err != nil {
+		log.Printf("[proxy] failed to relay progress notification: %v", err)
+	}
+}
+
+// inflightCalls tracks the tool calls being handled by the MCP proxy server,
+// so that downstream clients can cancel them with a notifications/cancelled notification.
+type inflightCalls struct {
+	mu sync.Mutex
+	// ids holds the JSON-RPC request IDs of the calls that were received but not handled yet.
+	// A tool handler cannot learn the ID of its request otherwise, so they are keyed by the context
+	// the request is handled with.
+	ids map[context.Context]any
+	// cancels holds the functions that cancel the calls being handled
+	cancels map[inflightCallKey]context.CancelFunc
+}
+
+// inflightCallKey identifies a request within the downstream MCP session that sent it.
+type inflightCallKey struct {
+	session string
+	id      string
+}
+
+func newInflightCalls() *inflightCalls {
+	return &inflightCalls{
+		ids:     make(map[context.Context]any),
+		cancels: make(map[inflightCallKey]context.CancelFunc),
+	}
+}
+
+// received records the request ID of a call about to be handled with ctx.
+func (c *inflightCalls) received(ctx context.Context, id any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[ctx] = id
+}
+
+// discard forgets a call that failed before it was handled.
+func (c *inflightCalls) discard(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.ids, ctx)
+}
+
+// start returns a context for handling the call received with ctx, which is cancelled
+// if the downstream client cancels the call.
+// done must be called once the call is over.
+func (c *inflightCalls) start(ctx context.Context) (context.Context, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.ids[ctx]
+	if !ok {
+		return ctx, func() {}
+	}
+	delete(c.ids, ctx)
+
+	key := newInflightCallKey(ctx, id)
+	callCtx, cancel := context.WithCancel(ctx)
+	c.cancels[key] = cancel
+	return callCtx, func() {
+		c.mu.Lock()
+		delete(c.cancels, key)
+		c.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel cancels the call referred to by a notifications/cancelled notification received with ctx.
+// It is a no-op if the call is already over.
+func (c *inflightCalls) cancel(ctx context.Context, n mcp.JSONRPCNotification) {
+	id, ok := n.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	cancel, ok := c.cancels[newInflightCallKey(ctx, id)]
+	c.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+func newInflightCallKey(ctx context.Context, id any) inflightCallKey {
+	var session string
+	if cs := server.ClientSessionFromContext(ctx); cs != nil {
+		session = cs.SessionID()
+	}
+	// request IDs may be numbers or strings, both decoded from JSON
+	return inflightCallKey{session: session, id: fmt.Sprint(id)}
+}
+
+// cancellingTransport wraps the transport of an upstream MCP client to tell the server when a request
+// is abandoned because its context was cancelled (eg- the downstream client cancelled the call or it timed out),
+// so that the server can stop working on it.
+// It also hands the raw result of a request over to the caller if it asked for it with withRawResult.
+type cancellingTransport struct {
+	transport.Interface
+}
+
+func (t *cancellingTransport) SendRequest(
+	ctx context.Context, request transport.JSONRPCRequest,
+) (*transport.JSONRPCResponse, error) {
+	resp, err := t.Interface.SendRequest(ctx, request)
+	if err != nil && ctx.Err() != nil && request.Method != string(mcp.MethodInitialize) {
+		t.notifyCancelled(request.ID, ctx.Err())
+	}
+	if raw, ok := ctx.Value(rawResultKey{}).(*json.RawMessage); ok && err == nil && resp.Error == nil {
+		*raw = resp.Result
+	}
+	return resp, err
+}
+
+// rawResultKey is the context key under which the result of a request is kept in its raw form.
+type rawResultKey struct{}
+
+// withRawResult returns a context in which the raw result of the request an upstream MCP client sends with it
+// is stored in raw, for the parts of results that mcp-go drops because it doesn't know them (eg- tool titles).
+// raw is left empty if the client doesn't wrap its transport in a cancellingTransport.
+func withRawResult(ctx context.Context, raw *json.RawMessage) context.Context {
+	return context.WithValue(ctx, rawResultKey{}, raw)
+}
+
+func (t *cancellingTransport) notifyCancelled(id mcp.RequestId, reason error) {
+	ctx, cancel := context.WithTimeout(context.Background(), upstreamCancelTimeout)
+	defer cancel()
+	n := mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: methodNotificationCancelled,
+			Params: mcp.NotificationParams{
+				AdditionalFields: map[string]any{"requestId": id, "reason": reason.Error()},
+			},
+		},
+	}
+	// the server may be unreachable, which is likely why the request was abandoned
+	_ = t.Interface.SendNotification(ctx, n)
+}
+
+// SetRequestHandler passes the handler of the requests sent by the server on to the wrapped transport,
+// if it can receive such requests.
+func (t *cancellingTransport) SetRequestHandler(handler transport.RequestHandler) {
+	if bt, ok := t.Interface.(transport.BidirectionalInterface); ok {
+		bt.SetRequestHandler(handler)
+	}
+}
+
+// unwrapTransport returns the transport wrapped by a cancellingTransport.
+func unwrapTransport(t transport.Interface) transport.Interface {
+	if ct, ok := t.(*cancellingTransport); ok {
+		return ct.Interface
+	}
+	return t
+}
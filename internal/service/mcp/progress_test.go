@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"testing"
+)
+
+func TestInflightCalls(t *testing.T) {
+	cancelled := func(id any) mcp.JSONRPCNotification {
+		return mcp.JSONRPCNotification{
+			Notification: mcp.Notification{
+				Method: methodNotificationCancelled,
+				Params: mcp.NotificationParams{AdditionalFields: map[string]any{"requestId": id}},
+			},
+		}
+	}
+
+	t.Run("cancels the call with the given request ID", func(t *testing.T) {
+		calls := newInflightCalls()
+		ctx := context.WithValue(context.Background(), "k", "v")
+		calls.received(ctx, float64(7))
+		callCtx, done := calls.start(ctx)
+		defer done()
+
+		calls.cancel(context.Background(), cancelled(float64(8)))
+		if callCtx.Err() != nil {
+			t.Fatal("call was cancelled by a notification for another request")
+		}
+		calls.cancel(context.Background(), cancelled(float64(7)))
+		if callCtx.Err() == nil {
+			t.Error("call was not cancelled")
+		}
+	})
+
+	t.Run("calls that were not received can't be cancelled", func(t *testing.T) {
+		calls := newInflightCalls()
+		ctx := context.Background()
+		callCtx, done := calls.start(ctx)
+		defer done()
+		if callCtx != ctx {
+			t.Error("start() returned a new context for a call that was not received")
+		}
+	})
+
+	t.Run("finished calls are forgotten", func(t *testing.T) {
+		calls := newInflightCalls()
+		ctx := context.WithValue(context.Background(), "k", "v")
+		calls.received(ctx, "req-1")
+		_, done := calls.start(ctx)
+		done()
+
+		other := context.WithValue(context.Background(), "k", "other")
+		calls.received(other, "req-2")
+		calls.discard(other)
+		if len(calls.ids) != 0 || len(calls.cancels) != 0 {
+			t.Errorf("ids = %v, cancels = %v, want both empty", calls.ids, calls.cancels)
+		}
+	})
+}
+
+// stubTransport is a transport whose requests block until their context is done.
+type stubTransport struct {
+	transport.Interface
+	notifications []mcp.JSONRPCNotification
+}
+
+func (t *stubTransport) SendRequest(ctx context.Context, _ transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (t *stubTransport) SendNotification(_ context.Context, n mcp.JSONRPCNotification) error {
+	t.notifications = append(t.notifications, n)
+	return nil
+}
+
+func TestCancellingTransport(t *testing.T) {
+	tests := []struct {
+		method     string
+		wantNotify bool
+	}{
+		{"tools/call", true},
+		{"initialize", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			stub := &stubTransport{}
+			ct := &cancellingTransport{stub}
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			req := transport.JSONRPCRequest{ID: mcp.NewRequestId(int64(3)), Method: tt.method}
+			if _, err := ct.SendRequest(ctx, req); err == nil {
+				t.Fatal("SendRequest() error = nil for a cancelled request")
+			}
+			if got := len(stub.notifications) == 1; got != tt.wantNotify {
+				t.Fatalf("sent %d notifications, want notification = %v", len(stub.notifications), tt.wantNotify)
+			}
+			if !tt.wantNotify {
+				return
+			}
+			n := stub.notifications[0]
+			if n.Method != methodNotificationCancelled || n.Params.AdditionalFields["requestId"] != req.ID {
+				t.Errorf("notification = %+v, want %s for request %v", n, methodNotificationCancelled, req.ID)
+			}
+		})
+	}
+}
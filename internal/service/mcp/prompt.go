@@ -0,0 +1,262 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"sort"
+)
+
+// ListPrompts returns all prompts registered in the registry.
+func (m *MCPService) ListPrompts() ([]model.Prompt, error) {
+	var prompts []model.Prompt
+	if err := m.db.Preload("Server").Order("server_id, name").Find(&prompts).Error; err != nil {
+		return nil, err
+	}
+	// prepend server name to prompt names to ensure we only return the unique names of prompts to user
+	for i := range prompts {
+		prompts[i].Name = joinServerName(prompts[i].Server.Name, prompts[i].Name)
+	}
+	return prompts, nil
+}
+
+// ListPromptsByServer fetches prompts provided by an MCP server from the registry.
+func (m *MCPService) ListPromptsByServer(name string) ([]model.Prompt, error) {
+	if err := validateServerName(name); err != nil {
+		return nil, err
+	}
+
+	s, err := m.GetMcpServer(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP server %s from DB: %w", name, err)
+	}
+
+	var prompts []model.Prompt
+	if err := m.db.Where("server_id = ?", s.ID).Order("name").Find(&prompts).Error; err != nil {
+		return nil, fmt.Errorf("failed to get prompts for server %s from DB: %w", name, err)
+	}
+
+	// prepend server name to prompt names to ensure we only return the unique names of prompts to user
+	for i := range prompts {
+		prompts[i].Name = joinServerName(s.Name, prompts[i].Name)
+	}
+	return prompts, nil
+}
+
+// fetchServerPrompts lists the prompts provided by an MCP server.
+// A server that doesn't announce the prompts capability provides none.
+func fetchServerPrompts(ctx context.Context, c *client.Client) ([]mcp.Prompt, error) {
+	if c.GetServerCapabilities().Prompts == nil {
+		return nil, nil
+	}
+	var prompts []mcp.Prompt
+	req := mcp.ListPromptsRequest{}
+	for {
+		resp, err := c.ListPrompts(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list prompts: %w", err)
+		}
+		prompts = append(prompts, resp.Prompts...)
+		if resp.NextCursor == "" {
+			return prompts, nil
+		}
+		req.Params.Cursor = resp.NextCursor
+	}
+}
+
+// registerServerPrompts fetches all prompts from an MCP server, registers them in the DB
+// and adds them to the MCP proxy server.
+func (m *MCPService) registerServerPrompts(ctx context.Context, s *model.McpServer, c *client.Client) error {
+	prompts, err := fetchServerPrompts(ctx, c)
+	if err != nil {
+		return fmt.Errorf("failed to fetch prompts from MCP server %s: %w", s.Name, err)
+	}
+	var changes *promptChanges
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		changes, err = replaceServerPrompts(tx, s, prompts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	m.applyPromptChanges(s, changes)
+	return nil
+}
+
+// promptChanges describes how the prompts of an MCP server changed in the DB,
+// so that the same changes can be made to the MCP proxy server.
+type promptChanges struct {
+	// prompts are the current prompts of the server, with their upstream names
+	prompts []mcp.Prompt
+	// removed holds the upstream names of the prompts that no longer exist
+	removed []string
+	changed bool
+}
+
+// replaceServerPrompts replaces the prompts of an MCP server in the DB with the given ones.
+// The DB is only written to if they differ from the ones already registered.
+func replaceServerPrompts(tx *gorm.DB, s *model.McpServer, prompts []mcp.Prompt) (*promptChanges, error) {
+	var existing []model.Prompt
+	if err := tx.Where("server_id = ?", s.ID).Order("name").Find(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to get prompts for server %s from DB: %w", s.Name, err)
+	}
+
+	// if the server listed the same prompt twice, only the first definition is used
+	seen := make(map[string]bool, len(prompts))
+	changes := &promptChanges{prompts: make([]mcp.Prompt, 0, len(prompts))}
+	models := make([]model.Prompt, 0, len(prompts))
+	for _, p := range prompts {
+		if seen[p.Name] {
+			continue
+		}
+		seen[p.Name] = true
+		changes.prompts = append(changes.prompts, p)
+		models = append(models, *newPromptModel(s, p))
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+
+	for _, old := range existing {
+		if !seen[old.Name] {
+			changes.removed = append(changes.removed, old.Name)
+		}
+	}
+	if !promptsChanged(existing, models) {
+		return changes, nil
+	}
+	changes.changed = true
+
+	if err := tx.Unscoped().Where("server_id = ?", s.ID).Delete(&model.Prompt{}).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete prompts for server %s: %w", s.Name, err)
+	}
+	if len(models) > 0 {
+		if err := tx.Create(&models).Error; err != nil {
+			return nil, fmt.Errorf("failed to register prompts for server %s: %w", s.Name, err)
+		}
+	}
+	return changes, nil
+}
+
+// applyPromptChanges reflects the changes made to the prompts of an MCP server in the DB in the MCP proxy server.
+func (m *MCPService) applyPromptChanges(s *model.McpServer, changes *promptChanges) {
+	if !changes.changed {
+		return
+	}
+	if len(changes.removed) > 0 {
+		removed := make([]string, len(changes.removed))
+		for i, name := range changes.removed {
+			removed[i] = joinServerName(s.Name, name)
+		}
+		m.mcpProxyServer.DeletePrompts(removed...)
+	}
+	if len(changes.prompts) > 0 {
+		// AddPrompts replaces the definition of an existing prompt
+		serverPrompts := make([]server.ServerPrompt, len(changes.prompts))
+		for i, p := range changes.prompts {
+			p.Name = joinServerName(s.Name, p.Name)
+			serverPrompts[i] = server.ServerPrompt{Prompt: p, Handler: m.mcpProxyGetPromptHandler}
+		}
+		m.mcpProxyServer.AddPrompts(serverPrompts...)
+	}
+}
+
+// newPromptModel converts a prompt provided by an MCP server into its DB model.
+func newPromptModel(s *model.McpServer, p mcp.Prompt) *model.Prompt {
+	var arguments []byte
+	if len(p.Arguments) > 0 {
+		arguments, _ = json.Marshal(p.Arguments)
+	}
+	return &model.Prompt{
+		ServerID:    s.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Arguments:   arguments,
+	}
+}
+
+// newProxyPrompt converts a prompt stored in the DB into its definition in the MCP proxy server.
+func newProxyPrompt(p *model.Prompt) (mcp.Prompt, error) {
+	prompt := mcp.NewPrompt(p.Name, mcp.WithPromptDescription(p.Description))
+	if len(p.Arguments) > 0 {
+		if err := json.Unmarshal(p.Arguments, &prompt.Arguments); err != nil {
+			return prompt, fmt.Errorf("failed to unmarshal arguments %s for prompt %s: %w", p.Arguments, p.Name, err)
+		}
+	}
+	return prompt, nil
+}
+
+// promptsChanged returns true if the prompts differ from the ones stored in the DB.
+// Both lists must be sorted by name.
+func promptsChanged(old, prompts []model.Prompt) bool {
+	if len(old) != len(prompts) {
+		return true
+	}
+	for i := range old {
+		if old[i].Name != prompts[i].Name ||
+			old[i].Description != prompts[i].Description ||
+			!jsonEqual(old[i].Arguments, prompts[i].Arguments) {
+			return true
+		}
+	}
+	return false
+}
+
+// deregisterServerPrompts deletes all prompts that belong to an MCP server from the DB.
+// It also removes the prompts from the MCP proxy server.
+func (m *MCPService) deregisterServerPrompts(s *model.McpServer) error {
+	prompts, err := m.ListPromptsByServer(s.Name)
+	if err != nil {
+		return fmt.Errorf("failed to list prompts for server %s: %w", s.Name, err)
+	}
+	if len(prompts) == 0 {
+		return nil
+	}
+
+	if err := m.db.Unscoped().Where("server_id = ?", s.ID).Delete(&model.Prompt{}).Error; err != nil {
+		return fmt.Errorf("failed to delete prompts for server %s: %w", s.Name, err)
+	}
+
+	names := make([]string, len(prompts))
+	for i, p := range prompts {
+		names[i] = p.Name
+	}
+	m.mcpProxyServer.DeletePrompts(names...)
+	return nil
+}
+
+// mcpProxyGetPromptHandler handles prompt requests for the MCP proxy server
+// by forwarding the request to the upstream MCP server that provides the prompt and
+// relaying the response back.
+func (m *MCPService) mcpProxyGetPromptHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	if err := checkNotInToolset(ctx, "prompts"); err != nil {
+		return nil, err
+	}
+	serverName, promptName, ok := splitServerToolName(request.Params.Name)
+	if !ok {
+		return nil, fmt.Errorf("invalid input: prompt name does not contain a %s separator", toolNames.separator)
+	}
+	if err := checkClientServerAccess(ctx, serverName); err != nil {
+		return nil, err
+	}
+
+	s, err := m.GetMcpServer(serverName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get details about MCP server %s from DB: %w", serverName, err)
+	}
+
+	// Ensure the prompt name is set correctly, ie, without the server name prefix
+	request.Params.Name = promptName
+
+	// getting a prompt has no side effects, so it is always safe to retry
+	var result *mcp.GetPromptResult
+	err = m.callMcpServer(ctx, s, true, func(ctx context.Context, c *client.Client) error {
+		var err error
+		result, err = c.GetPrompt(ctx, request)
+		return err
+	})
+	return result, err
+}
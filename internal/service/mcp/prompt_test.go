@@ -0,0 +1,30 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"testing"
+)
+
+func TestFetchServerPrompts(t *testing.T) {
+	// a page size of 1 forces the prompts to be listed over several pages
+	srv := server.NewMCPServer("test", "0.0.0", server.WithPromptCapabilities(false), server.WithPaginationLimit(1))
+	for i := 1; i <= 3; i++ {
+		srv.AddPrompt(
+			mcp.NewPrompt(fmt.Sprintf("prompt-%d", i)),
+			func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				return &mcp.GetPromptResult{}, nil
+			},
+		)
+	}
+
+	prompts, err := fetchServerPrompts(context.Background(), newInProcessClient(t, srv))
+	if err != nil {
+		t.Fatalf("fetchServerPrompts() error = %v", err)
+	}
+	if len(prompts) != 3 {
+		t.Errorf("fetchServerPrompts() returned %d prompts, want 3", len(prompts))
+	}
+}
@@ -2,35 +2,189 @@ package mcp
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"log"
 )
 
 // initMCPProxyServer initializes the MCP proxy server.
-// It loads all the registered MCP tools from the database into the proxy server.
+// It loads all the registered MCP tools and prompts from the database into the proxy server.
 func (m *MCPService) initMCPProxyServer() error {
 	tools, err := m.ListTools()
 	if err != nil {
 		return fmt.Errorf("failed to list tools from DB: %w", err)
 	}
-	for _, tm := range tools {
-		// Add tool to the MCP proxy server
-		tool := mcp.NewTool(tm.Name)
-		tool.Description = tm.Description
+	for i := range tools {
+		if !tools[i].Enabled {
+			continue
+		}
+		tool, err := newProxyTool(&tools[i])
+		if err != nil {
+			return err
+		}
+		m.mcpProxyServer.AddTool(tool, m.mcpProxyToolCallHandler)
+	}
 
-		var inputSchema mcp.ToolInputSchema
-		if err := json.Unmarshal(tm.InputSchema, &inputSchema); err != nil {
-			return fmt.Errorf(
-				"failed to unmarshal input schema %s for tool %s: %w", tm.InputSchema, tm.Name, err,
-			)
+	prompts, err := m.ListPrompts()
+	if err != nil {
+		return fmt.Errorf("failed to list prompts from DB: %w", err)
+	}
+	for i := range prompts {
+		prompt, err := newProxyPrompt(&prompts[i])
+		if err != nil {
+			return err
 		}
-		tool.InputSchema = inputSchema
+		m.mcpProxyServer.AddPrompt(prompt, m.mcpProxyGetPromptHandler)
+	}
 
-		// TODO: Add other attributes to the tool, such as annotations
+	m.mcpProxyServer.AddNotificationHandler(
+		methodNotificationCancelled,
+		func(ctx context.Context, n mcp.JSONRPCNotification) {
+			m.calls.cancel(ctx, n)
+		},
+	)
 
-		m.mcpProxyServer.AddTool(tool, m.mcpProxyToolCallHandler)
+	// The resources of all upstream servers are read through a single template matching their namespaced URIs.
+	// They are listed from the DB by the hooks added in RegisterProxyHooks().
+	m.mcpProxyServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(
+			proxyResourceURITemplate,
+			"MCPJungle resources",
+			mcp.WithTemplateDescription("Resources of the MCP servers registered in MCPJungle"),
+		),
+		m.mcpProxyReadResourceHandler,
+	)
+	return nil
+}
+
+// RegisterProxyHooks adds the hooks through which the MCP proxy server lists the resources, resource templates
+// and prompts of the registered MCP servers.
+// Resources are listed from the DB rather than from the proxy server itself, where a single template routes the reads
+// of all resources. All lists, including the list of tools, only contain what the MCP client is allowed to access.
+// The MCP servers of toolsets are served by the same proxy server: these hooks make them describe themselves
+// as the toolset and only list its tools.
+// It also adds the hooks that keep track of tool calls, so that MCP clients can cancel them, and of the sessions
+// of each MCP client, so that they can be notified when the client's access changes and receive the sampling
+// and elicitation requests of upstream MCP servers.
+func (m *MCPService) RegisterProxyHooks(hooks *server.Hooks) {
+	hooks.AddAfterInitialize(
+		func(ctx context.Context, _ any, request *mcp.InitializeRequest, result *mcp.InitializeResult) {
+			applyToolsetInfo(ctx, result)
+			m.downstreamSessions.bind(ctx, request.Params.Capabilities)
+		},
+	)
+	hooks.AddAfterListResources(
+		func(ctx context.Context, _ any, _ *mcp.ListResourcesRequest, result *mcp.ListResourcesResult) {
+			if checkNotInToolset(ctx, "resources") != nil {
+				result.Resources = []mcp.Resource{}
+				return
+			}
+			resources, err := m.listProxyResources(ctx)
+			if err != nil {
+				log.Printf("[proxy] failed to list resources: %v", err)
+			}
+			result.Resources = resources
+		},
+	)
+	hooks.AddAfterListResourceTemplates(
+		func(ctx context.Context, _ any, _ *mcp.ListResourceTemplatesRequest, result *mcp.ListResourceTemplatesResult) {
+			if checkNotInToolset(ctx, "resources") != nil {
+				result.ResourceTemplates = []mcp.ResourceTemplate{}
+				return
+			}
+			templates, err := m.listProxyResourceTemplates(ctx)
+			if err != nil {
+				log.Printf("[proxy] failed to list resource templates: %v", err)
+			}
+			result.ResourceTemplates = templates
+		},
+	)
+	// remember the request IDs of tool calls, so that downstream clients can cancel them
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, _ *mcp.CallToolRequest) {
+		m.calls.received(ctx, id)
+	})
+	hooks.AddOnError(func(ctx context.Context, _ any, method mcp.MCPMethod, _ any, _ error) {
+		if method == mcp.MethodToolsCall {
+			m.calls.discard(ctx)
+		}
+	})
+	hooks.AddAfterListTools(
+		func(ctx context.Context, _ any, _ *mcp.ListToolsRequest, result *mcp.ListToolsResult) {
+			var matcher *toolsetMatcher
+			var refs map[string]toolRef
+			if t := toolsetFromContext(ctx); t != nil {
+				var err error
+				if matcher, err = newToolsetMatcher(t); err == nil {
+					// toolsets select tools by their upstream names, which can't always be derived from the exposed ones
+					refs, err = m.exposedToolRefs()
+				}
+				if err != nil {
+					log.Printf("[proxy] failed to list tools of toolset %s: %v", t.Name, err)
+					result.Tools = []mcp.Tool{}
+					return
+				}
+			}
+			tools := make([]mcp.Tool, 0, len(result.Tools))
+			for _, t := range result.Tools {
+				serverName, toolName, _ := splitServerToolName(t.Name)
+				if checkClientServerAccess(ctx, serverName) != nil {
+					continue
+				}
+				if matcher == nil {
+					tools = append(tools, t)
+					continue
+				}
+				if ref, ok := refs[t.Name]; ok {
+					serverName, toolName = ref.server, ref.tool
+				}
+				if matcher.matches(serverName, toolName) {
+					tools = append(tools, t)
+				}
+			}
+			result.Tools = tools
+		},
+	)
+	hooks.AddAfterListPrompts(
+		func(ctx context.Context, _ any, _ *mcp.ListPromptsRequest, result *mcp.ListPromptsResult) {
+			if checkNotInToolset(ctx, "prompts") != nil {
+				result.Prompts = []mcp.Prompt{}
+				return
+			}
+			prompts := make([]mcp.Prompt, 0, len(result.Prompts))
+			for _, p := range result.Prompts {
+				serverName, _, _ := splitServerToolName(p.Name)
+				if checkClientServerAccess(ctx, serverName) == nil {
+					prompts = append(prompts, p)
+				}
+			}
+			result.Prompts = prompts
+		},
+	)
+	hooks.AddOnRegisterSession(m.clientSessions.register)
+	hooks.AddOnUnregisterSession(m.clientSessions.unregister)
+	hooks.AddAfterSetLevel(func(ctx context.Context, _ any, _ *mcp.SetLevelRequest, _ *mcp.EmptyResult) {
+		m.logs.subscribe(ctx)
+	})
+	hooks.AddOnRegisterSession(m.downstreamSessions.attach)
+	hooks.AddOnUnregisterSession(m.downstreamSessions.detach)
+}
+
+// checkClientServerAccess returns an error if the MCP client that sent the request in ctx
+// is not authorized to access the given MCP server.
+// Access is only restricted in production mode.
+func checkClientServerAccess(ctx context.Context, serverName string) error {
+	if mode, _ := ctx.Value("mode").(model.ServerMode); mode != model.ModeProd {
+		return nil
+	}
+	c, ok := ctx.Value("client").(*model.McpClient)
+	if !ok {
+		return fmt.Errorf("unauthorized: no MCP client is associated with the request")
+	}
+	if !c.CheckHasServerAccess(serverName) {
+		return fmt.Errorf("client %s is not authorized to access MCP server %s", c.Name, serverName)
 	}
 	return nil
 }
@@ -40,23 +194,22 @@ func (m *MCPService) initMCPProxyServer() error {
 // relaying the response back.
 func (m *MCPService) mcpProxyToolCallHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	name := request.Params.Name
-	serverName, toolName, ok := splitServerToolName(name)
-	if !ok {
-		return nil, fmt.Errorf("invalid input: tool name does not contain a %s separator", serverToolNameSep)
+	serverName, toolName, err := m.resolveToolName(name)
+	if err != nil {
+		return nil, err
 	}
 
-	serverMode := ctx.Value("mode").(model.ServerMode)
-	if serverMode == model.ModeProd {
-		// In production mode, we need to check whether the MCP client is authorized to access the MCP server.
-		// If not, return error Unauthorized.
-		c := ctx.Value("client").(*model.McpClient)
-		if !c.CheckHasServerAccess(serverName) {
-			return nil, fmt.Errorf(
-				"client %s is not authorized to access MCP server %s", c.Name, serverName,
-			)
-		}
+	if err := checkClientServerAccess(ctx, serverName); err != nil {
+		return nil, err
+	}
+	if err := checkToolsetAccess(ctx, serverName, toolName); err != nil {
+		return nil, err
 	}
 
+	// the call is aborted, upstream too, if the downstream client cancels it
+	ctx, done := m.calls.start(ctx)
+	defer done()
+
 	// get the MCP server details from the database
 	server, err := m.GetMcpServer(serverName)
 	if err != nil {
@@ -65,18 +218,26 @@ func (m *MCPService) mcpProxyToolCallHandler(ctx context.Context, request mcp.Ca
 		)
 	}
 
-	// connect to the upstream MCP server that actually provides the tool
-	mcpClient, err := createMcpServerConn(ctx, server)
-	if err != nil {
-		return nil, fmt.Errorf(
-			"failed to create connection to MCP server %s: %w", serverName, err,
-		)
-	}
-	defer mcpClient.Close()
-
 	// Ensure the tool name is set correctly, ie, without the server name prefix
 	request.Params.Name = toolName
 
-	// forward the request to the upstream MCP server and relay the response back
-	return mcpClient.CallTool(ctx, request)
+	// progress notifications sent by the upstream server are relayed back to the downstream client
+	if request.Params.Meta != nil && request.Params.Meta.ProgressToken != nil {
+		token, release := m.progress.register(ctx, request.Params.Meta.ProgressToken)
+		defer release()
+		meta := *request.Params.Meta
+		meta.ProgressToken = token
+		request.Params.Meta = &meta
+	}
+
+	// forward the request to the upstream MCP server that actually provides the tool
+	// and relay the response back. Only calls to read-only tools are safe to retry.
+	retryable := m.isReadOnlyTool(server, toolName)
+	var result *mcp.CallToolResult
+	err = m.callMcpServer(ctx, server, retryable, func(ctx context.Context, c *client.Client) error {
+		var err error
+		result, err = c.CallTool(ctx, request)
+		return err
+	})
+	return result, err
 }
@@ -0,0 +1,348 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"log"
+	"sort"
+)
+
+// upstreamResources holds the resources and resource templates provided by an MCP server.
+type upstreamResources struct {
+	resources []mcp.Resource
+	templates []mcp.ResourceTemplate
+}
+
+// fetchServerResources lists the resources and resource templates provided by an MCP server.
+// A server that doesn't announce the resources capability provides none.
+// Resource templates are optional, so a server that fails to list them is considered to provide none.
+func fetchServerResources(ctx context.Context, c *client.Client) (*upstreamResources, error) {
+	r := &upstreamResources{}
+	if c.GetServerCapabilities().Resources == nil {
+		return r, nil
+	}
+	req := mcp.ListResourcesRequest{}
+	for {
+		resp, err := c.ListResources(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources: %w", err)
+		}
+		r.resources = append(r.resources, resp.Resources...)
+		if resp.NextCursor == "" {
+			break
+		}
+		req.Params.Cursor = resp.NextCursor
+	}
+
+	templatesReq := mcp.ListResourceTemplatesRequest{}
+	for {
+		resp, err := c.ListResourceTemplates(ctx, templatesReq)
+		if err != nil {
+			log.Printf("[resources] failed to list resource templates, assuming there are none: %v", err)
+			r.templates = nil
+			break
+		}
+		r.templates = append(r.templates, resp.ResourceTemplates...)
+		if resp.NextCursor == "" {
+			break
+		}
+		templatesReq.Params.Cursor = resp.NextCursor
+	}
+	return r, nil
+}
+
+// registerServerResources fetches all resources and resource templates from an MCP server and registers them in the DB.
+func (m *MCPService) registerServerResources(ctx context.Context, s *model.McpServer, c *client.Client) error {
+	r, err := fetchServerResources(ctx, c)
+	if err != nil {
+		return fmt.Errorf("failed to fetch resources from MCP server %s: %w", s.Name, err)
+	}
+	var changed bool
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		changed, err = replaceServerResources(tx, s, r)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if changed {
+		m.notifyResourcesChanged()
+	}
+	return nil
+}
+
+// replaceServerResources replaces the resources and resource templates of an MCP server in the DB with the given ones.
+// The DB is only written to if they differ from the ones already registered.
+// It returns true if anything changed.
+func replaceServerResources(tx *gorm.DB, s *model.McpServer, r *upstreamResources) (bool, error) {
+	var existing []model.Resource
+	if err := tx.Where("server_id = ?", s.ID).Order("uri").Find(&existing).Error; err != nil {
+		return false, fmt.Errorf("failed to get resources for server %s from DB: %w", s.Name, err)
+	}
+	var existingTemplates []model.ResourceTemplate
+	if err := tx.Where("server_id = ?", s.ID).Order("uri_template").Find(&existingTemplates).Error; err != nil {
+		return false, fmt.Errorf("failed to get resource templates for server %s from DB: %w", s.Name, err)
+	}
+
+	resources := newResourceModels(s, r.resources)
+	templates := newResourceTemplateModels(s, r.templates)
+	if !resourcesChanged(existing, resources) && !resourceTemplatesChanged(existingTemplates, templates) {
+		return false, nil
+	}
+
+	if err := deleteServerResources(tx, s); err != nil {
+		return false, err
+	}
+	if len(resources) > 0 {
+		if err := tx.Create(&resources).Error; err != nil {
+			return false, fmt.Errorf("failed to register resources for server %s: %w", s.Name, err)
+		}
+	}
+	if len(templates) > 0 {
+		if err := tx.Create(&templates).Error; err != nil {
+			return false, fmt.Errorf("failed to register resource templates for server %s: %w", s.Name, err)
+		}
+	}
+	return true, nil
+}
+
+// deleteServerResources deletes all resources and resource templates that belong to an MCP server from the DB.
+func deleteServerResources(tx *gorm.DB, s *model.McpServer) error {
+	if err := tx.Unscoped().Where("server_id = ?", s.ID).Delete(&model.Resource{}).Error; err != nil {
+		return fmt.Errorf("failed to delete resources for server %s: %w", s.Name, err)
+	}
+	if err := tx.Unscoped().Where("server_id = ?", s.ID).Delete(&model.ResourceTemplate{}).Error; err != nil {
+		return fmt.Errorf("failed to delete resource templates for server %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+// deregisterServerResources deletes all resources and resource templates that belong to an MCP server from the DB.
+// MCP clients are notified if the server provided any.
+func (m *MCPService) deregisterServerResources(s *model.McpServer) error {
+	var count int64
+	if err := m.db.Model(&model.Resource{}).Where("server_id = ?", s.ID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to count resources for server %s: %w", s.Name, err)
+	}
+	var templateCount int64
+	if err := m.db.Model(&model.ResourceTemplate{}).Where("server_id = ?", s.ID).Count(&templateCount).Error; err != nil {
+		return fmt.Errorf("failed to count resource templates for server %s: %w", s.Name, err)
+	}
+	if count+templateCount == 0 {
+		return nil
+	}
+	if err := m.db.Transaction(func(tx *gorm.DB) error { return deleteServerResources(tx, s) }); err != nil {
+		return err
+	}
+	m.notifyResourcesChanged()
+	return nil
+}
+
+// notifyResourcesChanged tells all MCP clients connected to the proxy that the list of resources changed.
+func (m *MCPService) notifyResourcesChanged() {
+	m.mcpProxyServer.SendNotificationToAllClients(mcp.MethodNotificationResourcesListChanged, nil)
+}
+
+// newResourceModels converts the resources provided by an MCP server into their DB models, sorted by URI.
+// If the server listed the same resource twice, only the first definition is used.
+func newResourceModels(s *model.McpServer, resources []mcp.Resource) []model.Resource {
+	seen := make(map[string]bool, len(resources))
+	models := make([]model.Resource, 0, len(resources))
+	for _, r := range resources {
+		if seen[r.URI] {
+			continue
+		}
+		seen[r.URI] = true
+		models = append(models, model.Resource{
+			ServerID:    s.ID,
+			URI:         r.URI,
+			Name:        r.Name,
+			Description: r.Description,
+			MimeType:    r.MIMEType,
+			Annotations: marshalAnnotations(r.Annotations),
+		})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].URI < models[j].URI })
+	return models
+}
+
+// newResourceTemplateModels converts the resource templates provided by an MCP server into their DB models,
+// sorted by URI template.
+// If the server listed the same template twice, only the first definition is used.
+func newResourceTemplateModels(s *model.McpServer, templates []mcp.ResourceTemplate) []model.ResourceTemplate {
+	seen := make(map[string]bool, len(templates))
+	models := make([]model.ResourceTemplate, 0, len(templates))
+	for _, t := range templates {
+		if t.URITemplate == nil || seen[t.URITemplate.Raw()] {
+			continue
+		}
+		seen[t.URITemplate.Raw()] = true
+		models = append(models, model.ResourceTemplate{
+			ServerID:    s.ID,
+			URITemplate: t.URITemplate.Raw(),
+			Name:        t.Name,
+			Description: t.Description,
+			MimeType:    t.MIMEType,
+			Annotations: marshalAnnotations(t.Annotations),
+		})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].URITemplate < models[j].URITemplate })
+	return models
+}
+
+// marshalAnnotations returns the JSON form of the annotations, or nil if there are none.
+func marshalAnnotations(a *mcp.Annotations) []byte {
+	if a == nil {
+		return nil
+	}
+	data, _ := json.Marshal(a)
+	return data
+}
+
+// resourcesChanged returns true if the resources differ from the ones stored in the DB.
+// Both lists must be sorted by URI.
+func resourcesChanged(old, resources []model.Resource) bool {
+	if len(old) != len(resources) {
+		return true
+	}
+	for i := range old {
+		if old[i].URI != resources[i].URI ||
+			old[i].Name != resources[i].Name ||
+			old[i].Description != resources[i].Description ||
+			old[i].MimeType != resources[i].MimeType ||
+			!jsonEqual(old[i].Annotations, resources[i].Annotations) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceTemplatesChanged returns true if the resource templates differ from the ones stored in the DB.
+// Both lists must be sorted by URI template.
+func resourceTemplatesChanged(old, templates []model.ResourceTemplate) bool {
+	if len(old) != len(templates) {
+		return true
+	}
+	for i := range old {
+		if old[i].URITemplate != templates[i].URITemplate ||
+			old[i].Name != templates[i].Name ||
+			old[i].Description != templates[i].Description ||
+			old[i].MimeType != templates[i].MimeType ||
+			!jsonEqual(old[i].Annotations, templates[i].Annotations) {
+			return true
+		}
+	}
+	return false
+}
+
+// listProxyResources returns the resources of all the MCP servers the MCP client in ctx is allowed to access,
+// with their URIs namespaced by server.
+func (m *MCPService) listProxyResources(ctx context.Context) ([]mcp.Resource, error) {
+	var resources []model.Resource
+	if err := m.db.Preload("Server").Order("server_id, uri").Find(&resources).Error; err != nil {
+		return []mcp.Resource{}, err
+	}
+	result := make([]mcp.Resource, 0, len(resources))
+	for _, r := range resources {
+		if checkClientServerAccess(ctx, r.Server.Name) != nil {
+			continue
+		}
+		resource := mcp.NewResource(mergeServerResourceURI(r.Server.Name, r.URI), r.Name)
+		resource.Description = r.Description
+		resource.MIMEType = r.MimeType
+		resource.Annotations = unmarshalAnnotations(r.Annotations)
+		result = append(result, resource)
+	}
+	return result, nil
+}
+
+// listProxyResourceTemplates returns the resource templates of all the MCP servers the MCP client in ctx
+// is allowed to access, with their URI templates namespaced by server.
+func (m *MCPService) listProxyResourceTemplates(ctx context.Context) ([]mcp.ResourceTemplate, error) {
+	var templates []model.ResourceTemplate
+	if err := m.db.Preload("Server").Order("server_id, uri_template").Find(&templates).Error; err != nil {
+		return []mcp.ResourceTemplate{}, err
+	}
+	result := make([]mcp.ResourceTemplate, 0, len(templates))
+	for _, t := range templates {
+		if checkClientServerAccess(ctx, t.Server.Name) != nil {
+			continue
+		}
+		template := mcp.NewResourceTemplate(mergeServerResourceURI(t.Server.Name, t.URITemplate), t.Name)
+		template.Description = t.Description
+		template.MIMEType = t.MimeType
+		template.Annotations = unmarshalAnnotations(t.Annotations)
+		result = append(result, template)
+	}
+	return result, nil
+}
+
+// unmarshalAnnotations parses annotations stored in the DB, returning nil if there are none.
+func unmarshalAnnotations(data []byte) *mcp.Annotations {
+	if len(data) == 0 {
+		return nil
+	}
+	var a mcp.Annotations
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil
+	}
+	return &a
+}
+
+// mcpProxyReadResourceHandler handles resource reads for the MCP proxy server
+// by forwarding the request to the upstream MCP server that provides the resource and
+// relaying the contents back with their URIs namespaced by server.
+func (m *MCPService) mcpProxyReadResourceHandler(
+	ctx context.Context, request mcp.ReadResourceRequest,
+) ([]mcp.ResourceContents, error) {
+	if err := checkNotInToolset(ctx, "resources"); err != nil {
+		return nil, err
+	}
+	serverName, uri, ok := splitServerResourceURI(request.Params.URI)
+	if !ok {
+		return nil, fmt.Errorf("invalid input: resource URI %s does not start with %s<server>/", request.Params.URI, proxyResourceURIPrefix)
+	}
+	if err := checkClientServerAccess(ctx, serverName); err != nil {
+		return nil, err
+	}
+
+	s, err := m.GetMcpServer(serverName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get details about MCP server %s from DB: %w", serverName, err)
+	}
+
+	// the upstream server only knows the resource by its own URI.
+	// The arguments were extracted by the proxy's catch-all template and mean nothing to the upstream server.
+	request.Params.URI = uri
+	request.Params.Arguments = nil
+
+	// reading a resource has no side effects, so it is always safe to retry
+	var result *mcp.ReadResourceResult
+	err = m.callMcpServer(ctx, s, true, func(ctx context.Context, c *client.Client) error {
+		var err error
+		result, err = c.ReadResource(ctx, request)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make([]mcp.ResourceContents, 0, len(result.Contents))
+	for _, c := range result.Contents {
+		switch rc := c.(type) {
+		case mcp.TextResourceContents:
+			rc.URI = mergeServerResourceURI(serverName, rc.URI)
+			c = rc
+		case mcp.BlobResourceContents:
+			rc.URI = mergeServerResourceURI(serverName, rc.URI)
+			c = rc
+		}
+		contents = append(contents, c)
+	}
+	return contents, nil
+}
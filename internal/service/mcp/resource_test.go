@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"testing"
+)
+
+// newInProcessClient returns an initialized client connected to the given MCP server.
+func newInProcessClient(t *testing.T, srv *server.MCPServer) *client.Client {
+	t.Helper()
+	c, err := client.NewInProcessClient(srv)
+	if err != nil {
+		t.Fatalf("failed to create in-process client: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("failed to start in-process client: %v", err)
+	}
+	req := mcp.InitializeRequest{}
+	req.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	if _, err := c.Initialize(ctx, req); err != nil {
+		t.Fatalf("failed to initialize in-process client: %v", err)
+	}
+	return c
+}
+
+func TestFetchServerResources(t *testing.T) {
+	newServer := func(templatesFail bool) *server.MCPServer {
+		hooks := &server.Hooks{}
+		hooks.AddOnRequestInitialization(func(ctx context.Context, id any, message any) error {
+			var req mcp.JSONRPCRequest
+			if raw, ok := message.(json.RawMessage); ok && json.Unmarshal(raw, &req) == nil &&
+				req.Method == string(mcp.MethodResourcesTemplatesList) && templatesFail {
+				return errors.New("templates are not supported")
+			}
+			return nil
+		})
+		// a page size of 1 forces the resources to be listed over several pages
+		srv := server.NewMCPServer(
+			"test", "0.0.0",
+			server.WithResourceCapabilities(false, false),
+			server.WithPaginationLimit(1),
+			server.WithHooks(hooks),
+		)
+		handler := func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return nil, nil
+		}
+		for i := 1; i <= 3; i++ {
+			srv.AddResource(mcp.NewResource(fmt.Sprintf("test://%d", i), fmt.Sprintf("resource %d", i)), handler)
+		}
+		templateHandler := func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return nil, nil
+		}
+		srv.AddResourceTemplate(mcp.NewResourceTemplate("test://a/{id}", "a"), templateHandler)
+		srv.AddResourceTemplate(mcp.NewResourceTemplate("test://b/{id}", "b"), templateHandler)
+		return srv
+	}
+
+	tests := []struct {
+		name          string
+		templatesFail bool
+		wantTemplates int
+	}{
+		{"all pages", false, 2},
+		{"templates fail", true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newInProcessClient(t, newServer(tt.templatesFail))
+			r, err := fetchServerResources(context.Background(), c)
+			if err != nil {
+				t.Fatalf("fetchServerResources() error = %v", err)
+			}
+			if len(r.resources) != 3 {
+				t.Errorf("fetchServerResources() returned %d resources, want 3", len(r.resources))
+			}
+			if len(r.templates) != tt.wantTemplates {
+				t.Errorf("fetchServerResources() returned %d templates, want %d", len(r.templates), tt.wantTemplates)
+			}
+		})
+	}
+}
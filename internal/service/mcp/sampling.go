@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// upstreamRequestRelay relays the requests an upstream MCP server sends to its client, ie- sampling and elicitation,
+// to the downstream session its connection is dedicated to.
+// The downstream client must have declared the matching capability when initializing its session,
+// and it must keep a stream open to receive the requests of the MCP proxy server.
+type upstreamRequestRelay struct {
+	proxy    *server.MCPServer
+	sessions *downstreamSessionSet
+
+	// server is the name of the upstream MCP server
+	server string
+	// session is the ID of the downstream session the requests are relayed to
+	session string
+
+	// sampling and elicitation tell which requests the downstream client accepts
+	sampling, elicitation bool
+}
+
+// newUpstreamRequestRelay returns the relay for a connection to an upstream MCP server dedicated to
+// a downstream session, or nil if the client of the session accepts neither sampling nor elicitation requests.
+func (m *MCPService) newUpstreamRequestRelay(serverName, session string) *upstreamRequestRelay {
+	if session == "" {
+		return nil
+	}
+	s, ok := m.downstreamSessions.get(session)
+	if !ok || (s.capabilities.Sampling == nil && s.capabilities.Elicitation == nil) {
+		return nil
+	}
+	return &upstreamRequestRelay{
+		proxy:       m.mcpProxyServer,
+		sessions:    m.downstreamSessions,
+		server:      serverName,
+		session:     session,
+		sampling:    s.capabilities.Sampling != nil,
+		elicitation: s.capabilities.Elicitation != nil,
+	}
+}
+
+// clientOptions returns the options that make an upstream MCP client advertise the capabilities
+// of the downstream client and handle the matching requests.
+func (r *upstreamRequestRelay) clientOptions() []client.ClientOption {
+	var opts []client.ClientOption
+	if r.sampling {
+		opts = append(opts, client.WithSamplingHandler(r))
+	}
+	if r.elicitation {
+		opts = append(opts, client.WithElicitationHandler(r))
+	}
+	return opts
+}
+
+// CreateMessage relays a sampling request to the downstream client.
+func (r *upstreamRequestRelay) CreateMessage(
+	ctx context.Context, request mcp.CreateMessageRequest,
+) (*mcp.CreateMessageResult, error) {
+	ctx, err := r.downstreamContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.proxy.RequestSampling(ctx, request)
+}
+
+// Elicit relays an elicitation request to the downstream client.
+func (r *upstreamRequestRelay) Elicit(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	ctx, err := r.downstreamContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.proxy.RequestElicitation(ctx, request)
+}
+
+// downstreamContext returns a context through which the MCP proxy server sends requests to the downstream session.
+func (r *upstreamRequestRelay) downstreamContext(ctx context.Context) (context.Context, error) {
+	s, ok := r.sessions.get(r.session)
+	if !ok || s.stream == nil {
+		return nil, fmt.Errorf(
+			"cannot relay the request of MCP server %s: the MCP client has no stream open to receive it", r.server,
+		)
+	}
+	return r.proxy.WithContext(ctx, s.stream), nil
+}
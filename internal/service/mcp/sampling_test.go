@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testDownstreamClient answers the sampling and elicitation requests it receives.
+type testDownstreamClient struct{}
+
+func (testDownstreamClient) CreateMessage(
+	_ context.Context, req mcp.CreateMessageRequest,
+) (*mcp.CreateMessageResult, error) {
+	question := req.Messages[0].Content.(mcp.TextContent).Text
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent("sampled: " + question)},
+		Model:           "test",
+	}, nil
+}
+
+func (testDownstreamClient) Elicit(_ context.Context, req mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	return &mcp.ElicitationResult{
+		ElicitationResponse: mcp.ElicitationResponse{
+			Action:  mcp.ElicitationResponseActionAccept,
+			Content: map[string]any{"answer": "elicited: " + req.Params.Message},
+		},
+	}, nil
+}
+
+func TestRelayUpstreamRequests(t *testing.T) {
+	upstream := server.NewMCPServer("upstream", "0.0.0", server.WithToolCapabilities(false))
+	upstream.AddTool(mcp.NewTool("sample"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := upstream.RequestSampling(ctx, mcp.CreateMessageRequest{
+			CreateMessageParams: mcp.CreateMessageParams{
+				Messages:  []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent("ping")}},
+				MaxTokens: 10,
+			},
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(result.Content.(mcp.TextContent).Text), nil
+	})
+	upstream.AddTool(mcp.NewTool("elicit"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := upstream.RequestElicitation(ctx, mcp.ElicitationRequest{
+			Params: mcp.ElicitationParams{Message: "pong", RequestedSchema: map[string]any{"type": "object"}},
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		answer, _ := result.Content.(map[string]any)["answer"].(string)
+		return mcp.NewToolResultText(answer), nil
+	})
+	upstreamSrv := server.NewTestStreamableHTTPServer(upstream)
+	t.Cleanup(upstreamSrv.Close)
+
+	m := newTestMCPService(t)
+	s := &model.McpServer{Name: "upstream", Transport: model.TransportStreamableHTTP, URL: upstreamSrv.URL + "/mcp"}
+	if err := m.db.Create(s).Error; err != nil {
+		t.Fatalf("failed to create MCP server: %v", err)
+	}
+	if _, err := m.syncServerTools(s, testUpstreamTools("sample", "elicit"), nil); err != nil {
+		t.Fatalf("syncServerTools() error = %v", err)
+	}
+	proxySrv := httptest.NewServer(server.NewStreamableHTTPServer(m.mcpProxyServer, server.WithSessionIdManager(m.SessionIdManager())))
+	t.Cleanup(proxySrv.Close)
+
+	tests := []struct {
+		name         string
+		capabilities []client.ClientOption
+		want         map[string]string
+	}{
+		{
+			name: "client supports sampling and elicitation",
+			capabilities: []client.ClientOption{
+				client.WithSamplingHandler(testDownstreamClient{}), client.WithElicitationHandler(testDownstreamClient{}),
+			},
+			want: map[string]string{"sample": "sampled: ping", "elicit": "elicited: pong"},
+		},
+		{
+			name:         "client supports sampling only",
+			capabilities: []client.ClientOption{client.WithSamplingHandler(testDownstreamClient{})},
+			want:         map[string]string{"sample": "sampled: ping", "elicit": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			tr, err := transport.NewStreamableHTTP(proxySrv.URL, transport.WithContinuousListening())
+			if err != nil {
+				t.Fatalf("failed to create transport: %v", err)
+			}
+			c := client.NewClient(tr, tt.capabilities...)
+			t.Cleanup(func() { _ = c.Close() })
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("failed to start client: %v", err)
+			}
+			if _, err := c.Initialize(ctx, mcp.InitializeRequest{}); err != nil {
+				t.Fatalf("failed to initialize client: %v", err)
+			}
+			// requests can only be relayed once the client listens for them
+			for {
+				if ds, _ := m.downstreamSessions.get(tr.GetSessionId()); ds.stream != nil {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					t.Fatal("the client never opened its stream")
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+
+			for tool, want := range tt.want {
+				req := mcp.CallToolRequest{}
+				req.Params.Name = mergeServerToolNames("upstream", tool)
+				result, err := c.CallTool(ctx, req)
+				if err != nil {
+					t.Fatalf("CallTool(%s) error = %v", tool, err)
+				}
+				text := result.Content[0].(mcp.TextContent).Text
+				if want == "" {
+					if !result.IsError {
+						t.Errorf("CallTool(%s) = %q, want an error since the client doesn't support it", tool, text)
+					}
+					continue
+				}
+				if result.IsError || text != want {
+					t.Errorf("CallTool(%s) = %q (error: %v), want %q", tool, text, result.IsError, want)
+				}
+			}
+		})
+	}
+}
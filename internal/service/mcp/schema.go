@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"strings"
+)
+
+// outputSchemaURL is the location the output schema of a tool is compiled under.
+// It only identifies the schema in error messages, nothing is loaded from it.
+const outputSchemaURL = "mcpjungle://output-schema.json"
+
+// validateOutputSchema checks the structured content returned by a tool against the tool's output schema.
+// Schemas without a $schema keyword are treated as JSON Schema draft 2020-12, the version the MCP spec uses.
+// References are only resolved within the schema itself, no other schema is ever loaded.
+func validateOutputSchema(schema []byte, content map[string]any) error {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return fmt.Errorf("invalid output schema: %w", err)
+	}
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	// an empty loader refuses all URLs, so that a schema can't make MCPJungle read files or send requests
+	c.UseLoader(jsonschema.SchemeURLLoader{})
+	if err := c.AddResource(outputSchemaURL, doc); err != nil {
+		return fmt.Errorf("invalid output schema: %w", err)
+	}
+	s, err := c.Compile(outputSchemaURL)
+	if err != nil {
+		return fmt.Errorf("invalid output schema: %w", err)
+	}
+
+	// round-trip the content so that all values have the types expected by the validator
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("failed to marshal structured content: %w", err)
+	}
+	v, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal structured content: %w", err)
+	}
+
+	err = s.Validate(v)
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+	var causes []string
+	collectValidationErrors(ve, &causes)
+	return errors.New(strings.Join(causes, "; "))
+}
+
+// collectValidationErrors appends the messages of the errors that caused a validation error to fail,
+// eg- "at '/count': got string, want integer".
+func collectValidationErrors(e *jsonschema.ValidationError, messages *[]string) {
+	if len(e.Causes) == 0 {
+		*messages = append(*messages, e.Error())
+		return
+	}
+	for _, cause := range e.Causes {
+		collectValidationErrors(cause, messages)
+	}
+}
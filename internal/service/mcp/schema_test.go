@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateOutputSchema(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"count": {"type": "integer", "minimum": 0},
+			"unit": {"type": "string", "enum": ["ms", "s"]},
+			"rows": {"type": "array", "items": {"type": "object", "required": ["id"]}},
+			"note": {"type": ["string", "null"]},
+			"id": {"$ref": "#/$defs/id"}
+		},
+		"required": ["count"],
+		"additionalProperties": false,
+		"$defs": {"id": {"type": "string", "pattern": "^[a-z]+$"}}
+	}`)
+
+	tests := []struct {
+		name    string
+		content map[string]any
+		wantErr string
+	}{
+		{"valid", map[string]any{"count": 3, "unit": "ms", "rows": []any{map[string]any{"id": 1}}}, ""},
+		{"nullable property", map[string]any{"count": 3, "note": nil}, ""},
+		{"missing required property", map[string]any{"unit": "ms"}, "at '': missing property 'count'"},
+		{"wrong type", map[string]any{"count": "3"}, "at '/count': got string, want integer"},
+		{"not an integer", map[string]any{"count": 1.5}, "at '/count': got number, want integer"},
+		{"value not in enum", map[string]any{"count": 1, "unit": "h"}, "at '/unit': value must be one of 'ms', 's'"},
+		{"invalid array item", map[string]any{"count": 1, "rows": []any{map[string]any{}}}, "at '/rows/0': missing property 'id'"},
+		{"additional property", map[string]any{"count": 1, "extra": true}, "additional properties 'extra' not allowed"},
+		{"below minimum", map[string]any{"count": -1}, "at '/count': minimum"},
+		{"referenced schema", map[string]any{"count": 1, "id": "ABC"}, "at '/id': 'ABC' does not match pattern"},
+		{"several errors", map[string]any{"count": "3", "unit": "h"}, "; "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOutputSchema(schema, tt.content)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateOutputSchema() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateOutputSchema() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateOutputSchemaDoesNotLoadReferences(t *testing.T) {
+	for _, ref := range []string{"file:///etc/passwd", "http://127.0.0.1:1/schema.json"} {
+		schema := []byte(`{"type": "object", "properties": {"a": {"$ref": "` + ref + `"}}}`)
+		err := validateOutputSchema(schema, map[string]any{"a": 1})
+		if err == nil || !strings.Contains(err.Error(), "invalid output schema") {
+			t.Errorf("validateOutputSchema() with $ref %s error = %v, want an invalid output schema", ref, err)
+		}
+	}
+}
+
+func TestValidateOutputSchemaDraft07(t *testing.T) {
+	schema := []byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {"n": {"$ref": "#/definitions/n"}},
+		"definitions": {"n": {"type": "integer"}}
+	}`)
+	if err := validateOutputSchema(schema, map[string]any{"n": 1}); err != nil {
+		t.Errorf("validateOutputSchema() error = %v, want nil", err)
+	}
+	if err := validateOutputSchema(schema, map[string]any{"n": "1"}); err == nil {
+		t.Error("validateOutputSchema() error = nil, want a type mismatch")
+	}
+}
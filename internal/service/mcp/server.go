@@ -3,40 +3,298 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"log"
+	"path/filepath"
+	"strings"
 )
 
 // RegisterMcpServer registers a new MCP server in the database.
-// It also registers all the Tools provided by the server.
-// Tool registration is on best-effort basis and does not fail the server registration.
+// It also registers all the Tools, Resources and Prompts provided by the server.
+// The server and its tools are registered in a single DB transaction, so a failed registration leaves nothing behind.
+// Resource and prompt registration is on best-effort basis and does not fail the server registration,
+// the resources and prompts are registered on the next sync instead.
 // Registered tools are also added to the MCP proxy server.
+// If the server has no name, it is named after the name the server reports about itself,
+// with a numeric suffix if another server is already registered with that name.
+// The version and instructions reported by the server are stored along with it.
 func (m *MCPService) RegisterMcpServer(ctx context.Context, s *model.McpServer) error {
-	if err := validateServerName(s.Name); err != nil {
+	if s.Name != "" {
+		if err := validateServerName(s.Name); err != nil {
+			return err
+		}
+		if err := validateServerNameForTools(s.Name); err != nil {
+			return err
+		}
+	}
+	if err := validateServerTransport(s); err != nil {
+		return err
+	}
+	if err := m.checkStdioAllowed(s); err != nil {
+		return err
+	}
+	if err := validateServerCallPolicy(s); err != nil {
 		return err
 	}
-
-	// TODO: validate the URL to ensure it is a valid HTTP/HTTPS URL (streamable http compliant)
 
 	// test that the server is reachable and is MCP-compliant
-	c, err := createMcpServerConn(ctx, s)
+	p, err := m.probeServer(ctx, s)
 	if err != nil {
+		if s.Name == "" {
+			return fmt.Errorf("failed to connect to MCP server: %w", err)
+		}
 		return fmt.Errorf("failed to connect to MCP server %s: %w", s.Name, err)
 	}
-	defer c.Close()
+	defer p.release()
 
-	// register the server in the DB
-	if err := m.db.Create(s).Error; err != nil {
-		return fmt.Errorf("failed to register mcp server: %w", err)
+	if s.Name == "" {
+		name := sanitizeServerName(p.info.ServerInfo.Name)
+		if name == "" {
+			return fmt.Errorf(
+				"MCP server did not report a usable name (%q), please specify one", p.info.ServerInfo.Name,
+			)
+		}
+		if s.Name, err = m.uniqueServerName(name); err != nil {
+			return err
+		}
+		if err := validateServerNameForTools(s.Name); err != nil {
+			return fmt.Errorf("%w, please specify another name", err)
+		}
 	}
+	applyServerInfo(s, p.info)
 
-	if err = m.registerServerTools(ctx, s, c); err != nil {
+	tools, err := fetchServerTools(ctx, p.client)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tools from MCP server %s: %w", s.Name, err)
+	}
+	// the server and its tools are registered in the DB in a single transaction,
+	// so that a failed registration leaves nothing behind and can simply be retried
+	_, err = m.syncServerTools(s, tools, func(tx *gorm.DB) error {
+		if err := tx.Create(s).Error; err != nil {
+			return fmt.Errorf("failed to register mcp server: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.Model = gorm.Model{}
 		return fmt.Errorf("failed to register tools for MCP server %s: %w", s.Name, err)
 	}
+	// the process of a stdio server is kept running (and supervised) once the server is registered,
+	// until then it is stopped along with the probe
+	m.adoptProbe(p, s.Name)
+	s.Process = m.stdioServers.status(s.Name)
+
+	if err = m.registerServerResources(ctx, s, p.client); err != nil {
+		log.Printf("[server] failed to register resources for MCP server %s: %v", s.Name, err)
+	}
+	if err = m.registerServerPrompts(ctx, s, p.client); err != nil {
+		log.Printf("[server] failed to register prompts for MCP server %s: %v", s.Name, err)
+	}
+
+	// report the health of the new server right away instead of waiting for the next round of checks
+	checked := *s
+	go m.checkServerHealth(&checked)
+
+	m.watchers.start(s)
+
 	return nil
 }
 
+// UpdateMcpServer replaces the settings of a registered MCP server with the given ones.
+// The new settings are validated by connecting to the server with them before anything changes,
+// so a failed update leaves the server as it was.
+// The settings, tools, resources and prompts of the server are updated in a single DB transaction,
+// and tools that still exist remain available in the MCP proxy server throughout.
+// The name of a server cannot be changed because it is part of the names of its tools.
+func (m *MCPService) UpdateMcpServer(ctx context.Context, name string, s *model.McpServer) error {
+	current, err := m.GetMcpServer(name)
+	if err != nil {
+		return fmt.Errorf("failed to get MCP server %s from DB: %w", name, err)
+	}
+	if s.Name != current.Name {
+		return fmt.Errorf("MCP server %s cannot be renamed", name)
+	}
+	s.Model = gorm.Model{ID: current.ID, CreatedAt: current.CreatedAt}
+	if err := validateServerTransport(s); err != nil {
+		return err
+	}
+	if err := m.checkStdioAllowed(s); err != nil {
+		return err
+	}
+	if err := validateServerCallPolicy(s); err != nil {
+		return err
+	}
+
+	// connect to the server with the new settings, the existing connections keep serving calls meanwhile
+	p, err := m.probeServer(ctx, s)
+	if err != nil {
+		return fmt.Errorf("failed to connect to MCP server %s with the new settings: %w", name, err)
+	}
+	defer p.release()
+	applyServerInfo(s, p.info)
+
+	tools, err := fetchServerTools(ctx, p.client)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tools from MCP server %s: %w", name, err)
+	}
+	resources, err := fetchServerResources(ctx, p.client)
+	if err != nil {
+		return fmt.Errorf("failed to fetch resources from MCP server %s: %w", name, err)
+	}
+	prompts, err := fetchServerPrompts(ctx, p.client)
+	if err != nil {
+		return fmt.Errorf("failed to fetch prompts from MCP server %s: %w", name, err)
+	}
+	var resourcesChanged bool
+	var promptResult *promptChanges
+	_, err = m.syncServerTools(s, tools, func(tx *gorm.DB) error {
+		if err := tx.Save(s).Error; err != nil {
+			return fmt.Errorf("failed to update MCP server %s: %w", name, err)
+		}
+		var err error
+		if resourcesChanged, err = replaceServerResources(tx, s, resources); err != nil {
+			return err
+		}
+		promptResult, err = replaceServerPrompts(tx, s, prompts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if resourcesChanged {
+		m.notifyResourcesChanged()
+	}
+	m.applyPromptChanges(s, promptResult)
+
+	// the new settings are in effect, tear down everything that was set up with the old ones
+	m.watchers.stop(name)
+	m.conns.drain(name)
+	evictOAuthTokenSources(name)
+	if p.proc != nil {
+		m.adoptProbe(p, name)
+	} else {
+		m.stdioServers.stop(name)
+	}
+	m.breakers.remove(name)
+	m.watchers.start(s)
+	s.Process = m.stdioServers.status(name)
+
+	checked := *s
+	go m.checkServerHealth(&checked)
+
+	return nil
+}
+
+// serverProbe is a connection made to an MCP server to validate its settings before they are stored.
+type serverProbe struct {
+	client *client.Client
+	// info is what the server reported about itself during initialization
+	info *mcp.InitializeResult
+	// proc is the process started for a stdio server.
+	// It only replaces the process currently backing the server once it is adopted.
+	proc    *stdioProcess
+	adopted bool
+}
+
+// probeServer connects to the MCP server using the given settings.
+// If no transport is specified, the HTTP-based transport supported by the server is detected and set on it.
+// The probe must be released once it is not needed anymore.
+func (m *MCPService) probeServer(ctx context.Context, s *model.McpServer) (*serverProbe, error) {
+	switch s.Transport {
+	case model.TransportStdio:
+		settings := *s
+		if settings.Name == "" {
+			// until the server reports its name, its process is labeled with its command
+			settings.Name = sanitizeServerName(filepath.Base(s.Command))
+		}
+		proc, err := m.stdioServers.prepare(ctx, &settings)
+		if err != nil {
+			return nil, err
+		}
+		c, err := proc.getClient()
+		if err != nil {
+			proc.stop()
+			return nil, err
+		}
+		return &serverProbe{client: c, info: proc.getInfo(), proc: proc}, nil
+	case "":
+		c, info, err := detectServerTransport(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		return &serverProbe{client: c, info: info}, nil
+	default:
+		c, info, err := createMcpServerConn(ctx, s, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		return &serverProbe{client: c, info: info}, nil
+	}
+}
+
+// adoptProbe hands the process started by the probe of a stdio server over to the supervisor,
+// which keeps it running as the process backing the server with the given name.
+// It is a no-op for HTTP-based servers.
+func (m *MCPService) adoptProbe(p *serverProbe, name string) {
+	if p.proc == nil {
+		return
+	}
+	p.proc.rename(name)
+	m.stdioServers.adopt(p.proc)
+	p.adopted = true
+}
+
+// release closes the connection of the probe, unless its process was adopted by the supervisor.
+func (p *serverProbe) release() {
+	switch {
+	case p.proc == nil:
+		_ = p.client.Close()
+	case !p.adopted:
+		p.proc.stop()
+	}
+}
+
+// uniqueServerName returns name if no MCP server is registered with it.
+// Otherwise, it returns name followed by the lowest numeric suffix (eg- name-2) that is not taken.
+func (m *MCPService) uniqueServerName(name string) (string, error) {
+	var taken []string
+	err := m.db.Unscoped().Model(&model.McpServer{}).
+		Where("name = ? OR name LIKE ?", name, name+"-%").
+		Pluck("name", &taken).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to list MCP servers from DB: %w", err)
+	}
+	isTaken := make(map[string]bool, len(taken))
+	for _, t := range taken {
+		isTaken[t] = true
+	}
+	if !isTaken[name] {
+		return name, nil
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		if !isTaken[candidate] {
+			return candidate, nil
+		}
+	}
+}
+
+// applyServerInfo stores the version and instructions the MCP server reported about itself.
+// If the server has no description, the first line of its instructions is used instead.
+func applyServerInfo(s *model.McpServer, info *mcp.InitializeResult) {
+	s.Version = info.ServerInfo.Version
+	s.Instructions = info.Instructions
+	if s.Description == "" {
+		first, _, _ := strings.Cut(strings.TrimSpace(info.Instructions), "\n")
+		s.Description = strings.TrimSpace(first)
+	}
+}
+
 // DeregisterMcpServer deregisters an MCP server from the database.
-// It also deregisters all the tools registered by the server.
+// It also deregisters all the tools, resources and prompts registered by the server.
 // If even a singe tool fails to deregister, the server deregistration fails.
 // A deregistered tool is also removed from the MCP proxy server.
 func (m *MCPService) DeregisterMcpServer(name string) error {
@@ -51,9 +309,29 @@ func (m *MCPService) DeregisterMcpServer(name string) error {
 			err,
 		)
 	}
+	if err := m.deregisterServerPrompts(s); err != nil {
+		return fmt.Errorf(
+			"failed to deregister prompts for server %s, cannot proceed with server deregistration: %w",
+			name,
+			err,
+		)
+	}
+	if err := m.deregisterServerResources(s); err != nil {
+		return fmt.Errorf(
+			"failed to deregister resources for server %s, cannot proceed with server deregistration: %w",
+			name,
+			err,
+		)
+	}
 	if err := m.db.Unscoped().Delete(s).Error; err != nil {
 		return fmt.Errorf("failed to deregister server %s: %w", name, err)
 	}
+	m.watchers.stop(name)
+	m.conns.drain(name)
+	evictOAuthTokenSources(name)
+	m.stdioServers.stop(name)
+	m.breakers.remove(name)
+	m.health.remove(name)
 	return nil
 }
 
@@ -63,6 +341,11 @@ func (m *MCPService) ListMcpServers() ([]model.McpServer, error) {
 	if err := m.db.Find(&servers).Error; err != nil {
 		return nil, err
 	}
+	for i := range servers {
+		servers[i].Process = m.stdioServers.status(servers[i].Name)
+		servers[i].CircuitBreaker = m.breakers.get(servers[i].Name).status()
+		servers[i].Health = m.health.get(servers[i].Name)
+	}
 	return servers, nil
 }
 
@@ -74,3 +357,25 @@ func (m *MCPService) GetMcpServer(name string) (*model.McpServer, error) {
 	}
 	return &serverModel, nil
 }
+
+// detectServerTransport determines which HTTP-based transport the MCP server supports and sets it on the server.
+// Streamable HTTP is attempted first since it is the current standard,
+// falling back to the legacy HTTP+SSE transport if the streamable HTTP handshake fails.
+func detectServerTransport(ctx context.Context, s *model.McpServer) (*client.Client, *mcp.InitializeResult, error) {
+	s.Transport = model.TransportStreamableHTTP
+	c, info, err := createMcpServerConn(ctx, s, nil, false)
+	if err == nil {
+		return c, info, nil
+	}
+
+	s.Transport = model.TransportSSE
+	c, info, sseErr := createMcpServerConn(ctx, s, nil, false)
+	if sseErr == nil {
+		return c, info, nil
+	}
+
+	s.Transport = ""
+	return nil, nil, fmt.Errorf(
+		"server does not support the streamable HTTP transport (%v) or the SSE transport (%v)", err, sseErr,
+	)
+}
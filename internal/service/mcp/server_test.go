@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegisterStdioServerNotAllowed(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	args, _ := json.Marshal([]string{marker})
+	s := &model.McpServer{
+		Name:      "evil",
+		Transport: model.TransportStdio,
+		Command:   "touch",
+		Args:      args,
+	}
+
+	m := &MCPService{}
+	err := m.RegisterMcpServer(context.Background(), s)
+	if !errors.Is(err, ErrStdioNotAllowed) {
+		t.Fatalf("RegisterMcpServer() error = %v, want %v", err, ErrStdioNotAllowed)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("the command of the rejected server was run")
+	}
+}
+
+func TestRegisterServerLeavesNothingOnToolFailure(t *testing.T) {
+	upstream := server.NewMCPServer("upstream", "0.0.0", server.WithToolCapabilities(false))
+	upstream.AddTool(mcp.NewTool("count"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("three"), nil
+	})
+	srv := server.NewTestStreamableHTTPServer(upstream)
+	t.Cleanup(srv.Close)
+
+	m := newTestMCPService(t)
+	failTools := true
+	err := m.db.Callback().Create().Before("gorm:create").Register("fail_tools", func(tx *gorm.DB) {
+		if failTools && tx.Statement.Table == "tools" {
+			_ = tx.AddError(errors.New("disk full"))
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to register DB callback: %v", err)
+	}
+
+	newServer := func() *model.McpServer {
+		return &model.McpServer{Name: "upstream", Transport: model.TransportStreamableHTTP, URL: srv.URL + "/mcp"}
+	}
+	if err := m.RegisterMcpServer(context.Background(), newServer()); err == nil {
+		t.Fatal("RegisterMcpServer() error = nil, want the tool registration failure")
+	}
+	var count int64
+	if err := m.db.Unscoped().Model(&model.McpServer{}).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count MCP servers: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("%d MCP servers left in the DB after the failed registration, want 0", count)
+	}
+
+	// the registration can be retried with the same name
+	failTools = false
+	if err := m.RegisterMcpServer(context.Background(), newServer()); err != nil {
+		t.Fatalf("RegisterMcpServer() retry error = %v", err)
+	}
+	tools, err := m.ListToolsByServer("upstream")
+	if err != nil || len(tools) != 1 {
+		t.Errorf("ListToolsByServer() = %v, %v, want the registered tool", tools, err)
+	}
+}
@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"sync"
+)
+
+// clientSessionSet keeps track of the downstream MCP sessions opened by each authenticated MCP client,
+// so that they can be told when what the client is allowed to access changes.
+// Only sessions that keep a stream open to receive notifications are tracked.
+type clientSessionSet struct {
+	mu sync.Mutex
+	// sessions holds the IDs of the sessions of each client, by client name
+	sessions map[string]map[string]bool
+}
+
+func newClientSessionSet() *clientSessionSet {
+	return &clientSessionSet{sessions: make(map[string]map[string]bool)}
+}
+
+// register records a session opened with ctx, if an MCP client is associated with it.
+func (cs *clientSessionSet) register(ctx context.Context, session server.ClientSession) {
+	c, ok := ctx.Value("client").(*model.McpClient)
+	if !ok {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.sessions[c.Name] == nil {
+		cs.sessions[c.Name] = make(map[string]bool)
+	}
+	cs.sessions[c.Name][session.SessionID()] = true
+}
+
+// unregister forgets a session opened with ctx.
+func (cs *clientSessionSet) unregister(ctx context.Context, session server.ClientSession) {
+	c, ok := ctx.Value("client").(*model.McpClient)
+	if !ok {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	delete(cs.sessions[c.Name], session.SessionID())
+	if len(cs.sessions[c.Name]) == 0 {
+		delete(cs.sessions, c.Name)
+	}
+}
+
+// list returns the IDs of the sessions of an MCP client.
+func (cs *clientSessionSet) list(clientName string) []string {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	ids := make([]string, 0, len(cs.sessions[clientName]))
+	for id := range cs.sessions[clientName] {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// downstreamSessionSet remembers what is known about each downstream session: the MCP endpoint it was initialized on,
+// the capabilities its client declared and the stream on which the client receives the requests of the MCP proxy server.
+type downstreamSessionSet struct {
+	mu       sync.Mutex
+	sessions map[string]*downstreamSession
+}
+
+// downstreamSession describes a downstream MCP session.
+type downstreamSession struct {
+	// initialized is true once the session was initialized by this instance of MCPJungle
+	initialized bool
+	// toolset is the name of the toolset the session was initialized on, empty for the main MCP server.
+	// The main MCP proxy server and the MCP servers of toolsets all share the same streamable HTTP server,
+	// but a session only describes the endpoint it was initialized on, so it must not be used on any other.
+	toolset string
+	// capabilities are the capabilities the client declared when initializing the session
+	capabilities mcp.ClientCapabilities
+	// stream is the session registered with the MCP proxy server while the client listens for its requests
+	// and notifications, nil if the client has no stream open
+	stream server.ClientSession
+}
+
+func newDownstreamSessionSet() *downstreamSessionSet {
+	return &downstreamSessionSet{sessions: make(map[string]*downstreamSession)}
+}
+
+// getOrAdd returns the session with the given ID, adding it if it is unknown.
+// The lock must be held by the caller.
+func (ds *downstreamSessionSet) getOrAdd(id string) *downstreamSession {
+	s, ok := ds.sessions[id]
+	if !ok {
+		s = &downstreamSession{}
+		ds.sessions[id] = s
+	}
+	return s
+}
+
+// bind records the endpoint and the client capabilities of the session initialized with ctx.
+func (ds *downstreamSessionSet) bind(ctx context.Context, capabilities mcp.ClientCapabilities) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || session.SessionID() == "" {
+		return
+	}
+	var toolset string
+	if t := toolsetFromContext(ctx); t != nil {
+		toolset = t.Name
+	}
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	s := ds.getOrAdd(session.SessionID())
+	s.initialized = true
+	s.toolset = toolset
+	s.capabilities = capabilities
+}
+
+// attach records the stream opened by the client of a session.
+func (ds *downstreamSessionSet) attach(_ context.Context, session server.ClientSession) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.getOrAdd(session.SessionID()).stream = session
+}
+
+// detach discards the stream of a session once the client closed it.
+func (ds *downstreamSessionSet) detach(_ context.Context, session server.ClientSession) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	s, ok := ds.sessions[session.SessionID()]
+	if !ok || s.stream != session {
+		return
+	}
+	if !s.initialized {
+		// the stream was opened without initializing a session first, there is nothing else to remember
+		delete(ds.sessions, session.SessionID())
+		return
+	}
+	s.stream = nil
+}
+
+// get returns what is known about the session with the given ID.
+func (ds *downstreamSessionSet) get(id string) (downstreamSession, bool) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	s, ok := ds.sessions[id]
+	if !ok {
+		return downstreamSession{}, false
+	}
+	return *s, true
+}
+
+// forget discards a terminated session.
+func (ds *downstreamSessionSet) forget(sessionID string) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	delete(ds.sessions, sessionID)
+}
+
+// ErrSessionEndpointMismatch is returned when a downstream session is used on another MCP endpoint
+// than the one it was initialized on.
+var ErrSessionEndpointMismatch = errors.New("the MCP session was initialized on another endpoint")
+
+// CheckSessionEndpoint returns ErrSessionEndpointMismatch if the given session was initialized on another MCP endpoint
+// than the one the request in ctx was sent to.
+// Sessions that weren't initialized by this instance of MCPJungle (eg- before a restart) are not bound to any endpoint.
+func (m *MCPService) CheckSessionEndpoint(ctx context.Context, sessionID string) error {
+	session, ok := m.downstreamSessions.get(sessionID)
+	if !ok || !session.initialized {
+		return nil
+	}
+	var toolset string
+	if t := toolsetFromContext(ctx); t != nil {
+		toolset = t.Name
+	}
+	if toolset != session.toolset {
+		return ErrSessionEndpointMismatch
+	}
+	return nil
+}
+
+// NotifyClientAccessChanged tells the open sessions of an MCP client that the tools, prompts and resources
+// it can see may have changed, eg- because its allow list was updated.
+// The client's requests are always checked against its current allow list, so the sessions only need to
+// list them again.
+func (m *MCPService) NotifyClientAccessChanged(clientName string) {
+	methods := []string{
+		mcp.MethodNotificationToolsListChanged,
+		mcp.MethodNotificationPromptsListChanged,
+		mcp.MethodNotificationResourcesListChanged,
+	}
+	for _, id := range m.clientSessions.list(clientName) {
+		for _, method := range methods {
+			// the session may have just been closed, there is no one left to notify then
+			_ = m.mcpProxyServer.SendNotificationToSpecificClient(id, method, nil)
+		}
+	}
+}
@@ -0,0 +1,433 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// stdioRestartBackoffMin is the delay before the first restart attempt of a crashed process.
+	stdioRestartBackoffMin = time.Second
+	// stdioRestartBackoffMax caps the delay between consecutive restart attempts.
+	stdioRestartBackoffMax = time.Minute
+	// stdioStableRunTime is how long a process must stay up before its restart backoff is reset.
+	stdioStableRunTime = time.Minute
+	// stdioStopTimeout is how long a process gets to exit on its own after its stdin is closed.
+	stdioStopTimeout = 5 * time.Second
+)
+
+// stdioSupervisor spawns and keeps alive the processes backing stdio MCP servers.
+// Each registered stdio server is backed by exactly one process and one MCP client session.
+type stdioSupervisor struct {
+	mu        sync.Mutex
+	processes map[string]*stdioProcess
+
+	// onNotification is called with every notification sent by any of the processes
+	onNotification func(server, session string, n mcp.JSONRPCNotification)
+}
+
+func newStdioSupervisor(onNotification func(server, session string, n mcp.JSONRPCNotification)) *stdioSupervisor {
+	return &stdioSupervisor{
+		processes:      make(map[string]*stdioProcess),
+		onNotification: onNotification,
+	}
+}
+
+// launch starts supervising the process for the given server without waiting for it to come up.
+// Failures to start are retried with backoff, so this is suitable for servers that were
+// registered in a previous run of MCPJungle.
+func (sv *stdioSupervisor) launch(s *model.McpServer) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if _, exists := sv.processes[s.Name]; exists {
+		return
+	}
+	p := newStdioProcess(s, sv.onNotification)
+	sv.processes[s.Name] = p
+
+	go func() {
+		err := p.spawn(context.Background())
+		if err != nil {
+			log.Printf("[stdio] failed to start MCP server %s: %v", s.Name, err)
+			p.setExited(err)
+		}
+		p.supervise()
+	}()
+}
+
+// prepare spawns a process with the given settings of a server and waits for it to complete the MCP handshake,
+// without touching the process currently backing the server.
+// The new process only takes over once it is passed to adopt(). If it is not needed anymore, it must be stopped.
+func (sv *stdioSupervisor) prepare(ctx context.Context, s *model.McpServer) (*stdioProcess, error) {
+	p := newStdioProcess(s, sv.onNotification)
+	if err := p.spawn(ctx); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// adopt makes a process returned by prepare() the one backing its server and starts supervising it.
+// The process that backed the server until now (if any) is stopped.
+func (sv *stdioSupervisor) adopt(p *stdioProcess) {
+	sv.mu.Lock()
+	old, ok := sv.processes[p.server.Name]
+	sv.processes[p.server.Name] = p
+	sv.mu.Unlock()
+
+	go p.supervise()
+	if ok {
+		old.stop()
+	}
+}
+
+// client returns the MCP client connected to the process of the given stdio server.
+func (sv *stdioSupervisor) client(name string) (*client.Client, error) {
+	sv.mu.Lock()
+	p, ok := sv.processes[name]
+	sv.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no process is running for MCP server %s", name)
+	}
+	return p.getClient()
+}
+
+// status returns the current process status of the given stdio server, or nil if it is not supervised.
+func (sv *stdioSupervisor) status(name string) *model.ProcessStatus {
+	sv.mu.Lock()
+	p, ok := sv.processes[name]
+	sv.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return p.getStatus()
+}
+
+// stop terminates the process of the given stdio server and stops supervising it.
+// It is a no-op if the server is not supervised.
+func (sv *stdioSupervisor) stop(name string) {
+	sv.mu.Lock()
+	p, ok := sv.processes[name]
+	delete(sv.processes, name)
+	sv.mu.Unlock()
+	if ok {
+		p.stop()
+	}
+}
+
+// stopAll terminates all supervised processes.
+func (sv *stdioSupervisor) stopAll() {
+	sv.mu.Lock()
+	processes := sv.processes
+	sv.processes = make(map[string]*stdioProcess)
+	sv.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, p := range processes {
+		wg.Add(1)
+		go func(p *stdioProcess) {
+			defer wg.Done()
+			p.stop()
+		}(p)
+	}
+	wg.Wait()
+}
+
+// stdioProcess is a single supervised process backing a stdio MCP server.
+type stdioProcess struct {
+	server         model.McpServer
+	onNotification func(server, session string, n mcp.JSONRPCNotification)
+
+	// nameMu guards the server's name against rename(), for the goroutines that read it concurrently
+	nameMu sync.RWMutex
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	client *client.Client
+	// info is what the process reported about itself during initialization
+	info   *mcp.InitializeResult
+	stderr *stderrLogger
+	status model.ProcessStatus
+	exited chan struct{}
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+func newStdioProcess(
+	s *model.McpServer, onNotification func(server, session string, n mcp.JSONRPCNotification),
+) *stdioProcess {
+	return &stdioProcess{
+		server:         *s,
+		onNotification: onNotification,
+		status:         model.ProcessStatus{State: model.ProcessStarting},
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// spawn starts a new process and initializes an MCP client session with it.
+// The process must complete the MCP handshake within the server's connect timeout.
+func (p *stdioProcess) spawn(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.server.GetConnectTimeout())
+	defer cancel()
+
+	args, err := p.server.GetArgs()
+	if err != nil {
+		return err
+	}
+	env, err := p.server.GetEnv()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(p.server.Command, args...)
+	cmd.Dir = p.server.WorkDir
+	cmd.Env = append(os.Environ(), env...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	// stdout is copied into an in-memory pipe so that the MCP client keeps reading until the
+	// process exits, and cmd.Wait() does not close the reader from under it.
+	stdoutReader, stdoutWriter := io.Pipe()
+	cmd.Stdout = stdoutWriter
+	stderr := &stderrLogger{server: p.server.Name}
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start command %s for MCP server %s: %w", p.server.Command, p.server.Name, err)
+	}
+
+	// the exit of the process is only recorded once spawn() is done with it,
+	// so that a process which dies right after the handshake is never reported as running.
+	spawned := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		err := cmd.Wait()
+		_ = stdoutWriter.Close()
+		<-spawned
+		p.setExited(err)
+		close(exited)
+	}()
+	abort := func(err error) error {
+		_ = cmd.Process.Kill()
+		close(spawned)
+		<-exited
+		return err
+	}
+
+	t := transport.NewIO(stdoutReader, stdin, io.NopCloser(strings.NewReader("")))
+	c := client.NewClient(&cancellingTransport{t})
+	if p.onNotification != nil {
+		c.OnNotification(func(n mcp.JSONRPCNotification) {
+			p.nameMu.RLock()
+			name := p.server.Name
+			p.nameMu.RUnlock()
+			// stdio connections are shared by all downstream sessions
+			p.onNotification(name, "", n)
+		})
+	}
+	if err := c.Start(context.Background()); err != nil {
+		return abort(fmt.Errorf("failed to start MCP client for MCP server %s: %w", p.server.Name, err))
+	}
+	info, err := initializeMcpClient(ctx, c, &p.server)
+	if err != nil {
+		return abort(err)
+	}
+	enableUpstreamLogging(ctx, c, info, p.server.Name)
+
+	now := time.Now()
+	p.mu.Lock()
+	select {
+	case <-p.stopCh:
+		// the process was stopped while it was starting up, don't leave it running unsupervised
+		p.mu.Unlock()
+		_ = c.Close()
+		return abort(fmt.Errorf("MCP server %s was stopped while starting up", p.server.Name))
+	default:
+	}
+	p.cmd = cmd
+	p.client = c
+	p.info = info
+	p.stderr = stderr
+	p.exited = exited
+	p.status.State = model.ProcessRunning
+	p.status.PID = cmd.Process.Pid
+	p.status.StartedAt = &now
+	p.status.LastError = ""
+	p.mu.Unlock()
+	close(spawned)
+
+	return nil
+}
+
+// supervise restarts the process with exponential backoff whenever it exits, until stop() is called.
+func (p *stdioProcess) supervise() {
+	backoff := stdioRestartBackoffMin
+	for {
+		p.mu.Lock()
+		exited := p.exited
+		startedAt := p.status.StartedAt
+		p.mu.Unlock()
+
+		if exited != nil {
+			select {
+			case <-exited:
+			case <-p.stopCh:
+				return
+			}
+			if startedAt != nil && time.Since(*startedAt) > stdioStableRunTime {
+				backoff = stdioRestartBackoffMin
+			}
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-p.stopCh:
+			return
+		}
+		backoff = min(backoff*2, stdioRestartBackoffMax)
+
+		p.mu.Lock()
+		p.status.Restarts++
+		p.mu.Unlock()
+
+		err := p.spawn(context.Background())
+		if err != nil {
+			log.Printf("[stdio] failed to restart MCP server %s: %v", p.server.Name, err)
+			p.setExited(err)
+			continue
+		}
+		log.Printf("[stdio] restarted MCP server %s (pid %d)", p.server.Name, p.getStatus().PID)
+	}
+}
+
+// setExited records that the process is no longer running.
+func (p *stdioProcess) setExited(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil {
+		_ = p.client.Close()
+		p.client = nil
+	}
+	p.cmd = nil
+	p.exited = nil
+	p.status.PID = 0
+	if err != nil {
+		p.status.LastError = err.Error()
+	}
+
+	select {
+	case <-p.stopCh:
+		p.status.State = model.ProcessStopped
+	default:
+		p.status.State = model.ProcessRestarting
+		if err == nil {
+			p.status.LastError = "process exited"
+		}
+	}
+}
+
+// stop terminates the process and prevents it from being restarted.
+// The process is first asked to exit by closing its stdin, and killed if it does not comply in time.
+func (p *stdioProcess) stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopCh)
+
+		p.mu.Lock()
+		cmd, c, exited := p.cmd, p.client, p.exited
+		p.status.State = model.ProcessStopped
+		p.mu.Unlock()
+
+		if c != nil {
+			_ = c.Close()
+		}
+		if cmd == nil || exited == nil {
+			return
+		}
+		select {
+		case <-exited:
+		case <-time.After(stdioStopTimeout):
+			_ = cmd.Process.Kill()
+			<-exited
+		}
+	})
+}
+
+func (p *stdioProcess) getClient() (*client.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client == nil {
+		return nil, fmt.Errorf(
+			"process for MCP server %s is not running (state: %s)", p.server.Name, p.status.State,
+		)
+	}
+	return p.client, nil
+}
+
+func (p *stdioProcess) getInfo() *mcp.InitializeResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.info
+}
+
+// rename changes the name of the server the process belongs to.
+// It is only safe to call before the process is adopted by the supervisor.
+func (p *stdioProcess) rename(name string) {
+	p.nameMu.Lock()
+	p.server.Name = name
+	p.nameMu.Unlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stderr != nil {
+		p.stderr.mu.Lock()
+		p.stderr.server = name
+		p.stderr.mu.Unlock()
+	}
+}
+
+func (p *stdioProcess) getStatus() *model.ProcessStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status := p.status
+	return &status
+}
+
+// stderrLogger writes each line a stdio MCP server prints on its stderr to MCPJungle's log.
+type stderrLogger struct {
+	server string
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (l *stderrLogger) Write(b []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buf = append(l.buf, b...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			// the last line is incomplete, wait for the rest of it
+			break
+		}
+		log.Printf("[stdio:%s] %s", l.server, bytes.TrimRight(l.buf[:i], "\r"))
+		l.buf = l.buf[i+1:]
+	}
+	return len(b), nil
+}
@@ -0,0 +1,284 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/types"
+	"gorm.io/gorm"
+	"log"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// SyncMcpServer re-fetches the tools provided by a registered MCP server and brings the registry up to date.
+// New tools are registered, tools that changed are updated and tools that no longer exist are deregistered,
+// both in the DB and in the MCP proxy server. Tools that didn't change remain available throughout.
+// The resources, resource templates and prompts of the server are refreshed in the same DB transaction.
+func (m *MCPService) SyncMcpServer(ctx context.Context, name string) (*types.ToolSyncResult, error) {
+	s, err := m.GetMcpServer(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP server %s from DB: %w", name, err)
+	}
+
+	var upstreamTools []upstreamTool
+	var resources *upstreamResources
+	var prompts []mcp.Prompt
+	err = m.callMcpServer(ctx, s, true, func(ctx context.Context, c *client.Client) error {
+		var err error
+		if upstreamTools, err = fetchServerTools(ctx, c); err != nil {
+			return err
+		}
+		if resources, err = fetchServerResources(ctx, c); err != nil {
+			return err
+		}
+		prompts, err = fetchServerPrompts(ctx, c)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tools, resources and prompts from MCP server %s: %w", s.Name, err)
+	}
+
+	var resourcesChanged bool
+	var promptResult *promptChanges
+	result, err := m.syncServerTools(s, upstreamTools, func(tx *gorm.DB) error {
+		var err error
+		if resourcesChanged, err = replaceServerResources(tx, s, resources); err != nil {
+			return err
+		}
+		promptResult, err = replaceServerPrompts(tx, s, prompts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resourcesChanged {
+		m.notifyResourcesChanged()
+	}
+	m.applyPromptChanges(s, promptResult)
+	return result, nil
+}
+
+// syncNotifiedServerTools syncs the tools of an MCP server after it notified that they changed.
+func (m *MCPService) syncNotifiedServerTools(ctx context.Context, name string) error {
+	result, err := m.SyncMcpServer(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(result.Added)+len(result.Updated)+len(result.Removed) > 0 {
+		log.Printf(
+			"[watch] synced tools of MCP server %s: added %v, updated %v, removed %v",
+			name, result.Added, result.Updated, result.Removed,
+		)
+	}
+	return nil
+}
+
+// syncServerTools reconciles the tools of an MCP server in the registry with the given list of upstream tools.
+// If before is not nil, it is run first within the same DB transaction, so that other changes can be
+// made atomically with the sync.
+func (m *MCPService) syncServerTools(
+	s *model.McpServer, upstreamTools []upstreamTool, before func(tx *gorm.DB) error,
+) (*types.ToolSyncResult, error) {
+	// syncs of the same server must not interleave, otherwise both could try to add the same tool
+	defer m.syncLocks.lock(s.Name)()
+
+	var added, updated []*model.Tool
+	var removed []string
+	// renamed holds the names the tools whose aliases were dropped used to be exposed with
+	var renamed []string
+
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if before != nil {
+			if err := before(tx); err != nil {
+				return err
+			}
+		}
+
+		var existing []model.Tool
+		if err := tx.Where("server_id = ?", s.ID).Find(&existing).Error; err != nil {
+			return fmt.Errorf("failed to get tools for server %s from DB: %w", s.Name, err)
+		}
+		existingByName := make(map[string]*model.Tool, len(existing))
+		for i := range existing {
+			existingByName[existing[i].Name] = &existing[i]
+		}
+
+		// an alias stops being valid once the server provides a tool with the same name,
+		// in which case the alias is dropped and the aliased tool goes back to its own name
+		upstreamNames := make(map[string]bool, len(upstreamTools))
+		for _, tool := range upstreamTools {
+			upstreamNames[tool.Name] = true
+		}
+		realiased := make(map[string]bool)
+		for i := range existing {
+			old := &existing[i]
+			alias := old.Overrides.Alias
+			if alias == "" || alias == old.Name || !upstreamNames[alias] || !upstreamNames[old.Name] {
+				continue
+			}
+			if err := tx.Model(old).Update("override_alias", "").Error; err != nil {
+				return fmt.Errorf("failed to drop alias %s of tool %s: %w", alias, joinServerName(s.Name, old.Name), err)
+			}
+			log.Printf(
+				"[sync] MCP server %s now provides a tool named %s, dropped it as the alias of tool %s",
+				s.Name, alias, old.Name,
+			)
+			renamed = append(renamed, exposedToolName(s.Name, old))
+			old.Overrides.Alias = ""
+			realiased[old.Name] = true
+		}
+
+		seen := make(map[string]bool, len(upstreamTools))
+		for _, tool := range upstreamTools {
+			if seen[tool.Name] {
+				// the server listed the same tool twice, only the first definition is used
+				continue
+			}
+			seen[tool.Name] = true
+
+			t := newToolModel(s, tool)
+			old, ok := existingByName[t.Name]
+			if !ok {
+				if err := tx.Create(t).Error; err != nil {
+					return fmt.Errorf("failed to register tool %s: %w", mergeServerToolNames(s.Name, t.Name), err)
+				}
+				added = append(added, t)
+				continue
+			}
+			if !toolChanged(old, t) {
+				if realiased[old.Name] {
+					updated = append(updated, old)
+				}
+				continue
+			}
+			err := tx.Model(old).Updates(map[string]any{
+				"title":         t.Title,
+				"description":   t.Description,
+				"input_schema":  t.InputSchema,
+				"output_schema": t.OutputSchema,
+				"annotations":   t.Annotations,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("failed to update tool %s: %w", mergeServerToolNames(s.Name, t.Name), err)
+			}
+			// the overrides set by admins are kept, and so is the tool disabled if it was
+			t.Overrides = old.Overrides
+			t.Enabled = old.Enabled
+			updated = append(updated, t)
+		}
+
+		for _, old := range existing {
+			if seen[old.Name] {
+				continue
+			}
+			if err := tx.Unscoped().Delete(&model.Tool{}, old.ID).Error; err != nil {
+				return fmt.Errorf("failed to deregister tool %s: %w", mergeServerToolNames(s.Name, old.Name), err)
+			}
+			removed = append(removed, exposedToolName(s.Name, &old))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the DB is up to date, now reflect the changes in the MCP proxy server
+	// (AddTool replaces the definition of an existing tool)
+	result := &types.ToolSyncResult{Added: []string{}, Updated: []string{}, Removed: []string{}}
+	if len(renamed) > 0 {
+		// removed before adding the tools that now own these names
+		m.mcpProxyServer.DeleteTools(renamed...)
+	}
+	for _, t := range added {
+		if err := m.addProxyTool(s, t); err != nil {
+			return nil, err
+		}
+		result.Added = append(result.Added, exposedToolName(s.Name, t))
+	}
+	for _, t := range updated {
+		if err := m.addProxyTool(s, t); err != nil {
+			return nil, err
+		}
+		result.Updated = append(result.Updated, exposedToolName(s.Name, t))
+	}
+	if len(removed) > 0 {
+		m.mcpProxyServer.DeleteTools(removed...)
+		result.Removed = removed
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Updated)
+	sort.Strings(result.Removed)
+	return result, nil
+}
+
+// toolChanged returns true if the definition of a tool differs from the one stored in the DB.
+func toolChanged(old, t *model.Tool) bool {
+	return old.Title != t.Title ||
+		old.Description != t.Description ||
+		!jsonEqual(old.InputSchema, t.InputSchema) ||
+		!jsonEqual(old.OutputSchema, t.OutputSchema) ||
+		!jsonEqual(old.Annotations, t.Annotations)
+}
+
+// jsonEqual returns true if both JSON documents have the same content, regardless of formatting and key order.
+// Empty documents are treated as equal to JSON null.
+func jsonEqual(a, b []byte) bool {
+	var va, vb any
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &va); err != nil {
+			return false
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &vb); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// serverLockSet holds a mutex per MCP server, keyed by server name.
+// A mutex only exists while it is held or waited for, so the set doesn't grow with deregistered servers.
+type serverLockSet struct {
+	mu    sync.Mutex
+	locks map[string]*serverLock
+}
+
+type serverLock struct {
+	sync.Mutex
+	// refs is the number of callers holding or waiting for the mutex
+	refs int
+}
+
+func newServerLockSet() *serverLockSet {
+	return &serverLockSet{locks: make(map[string]*serverLock)}
+}
+
+// lock locks the mutex of the given server and returns the function that unlocks it.
+func (ls *serverLockSet) lock(name string) func() {
+	ls.mu.Lock()
+	l, ok := ls.locks[name]
+	if !ok {
+		l = &serverLock{}
+		ls.locks[name] = l
+	}
+	l.refs++
+	ls.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		ls.mu.Lock()
+		defer ls.mu.Unlock()
+		l.refs--
+		if l.refs == 0 {
+			delete(ls.locks, name)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/glebarez/sqlite"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// newTestMCPService returns an MCP service backed by a fresh sqlite database.
+func newTestMCPService(t *testing.T) *MCPService {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "mcp.db")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	if err := migrations.Migrate(db); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+	hooks := &server.Hooks{}
+	proxy := server.NewMCPServer(
+		"test", "0.0.0", server.WithToolCapabilities(true), server.WithLogging(), server.WithHooks(hooks),
+	)
+	m, err := NewMCPService(db, proxy)
+	if err != nil {
+		t.Fatalf("failed to create MCP service: %v", err)
+	}
+	m.RegisterProxyHooks(hooks)
+	t.Cleanup(m.Shutdown)
+	return m
+}
+
+// testUpstreamTools returns minimal upstream definitions of tools with the given names.
+func testUpstreamTools(names ...string) []upstreamTool {
+	tools := make([]upstreamTool, len(names))
+	for i, name := range names {
+		tools[i] = upstreamTool{Name: name, InputSchema: json.RawMessage(`{"type": "object"}`)}
+	}
+	return tools
+}
+
+func TestToolChanged(t *testing.T) {
+	base := model.Tool{
+		Name:        "add",
+		Description: "add two numbers",
+		InputSchema: []byte(`{"type": "object", "properties": {"a": {"type": "number"}, "b": {"type": "number"}}}`),
+		Annotations: []byte(`{"readOnlyHint": true}`),
+	}
+	tests := []struct {
+		name   string
+		modify func(t *model.Tool)
+		want   bool
+	}{
+		{"identical", func(t *model.Tool) {}, false},
+		{
+			"schema formatted differently",
+			func(t *model.Tool) {
+				t.InputSchema = []byte(`{"properties":{"b":{"type":"number"},"a":{"type":"number"}},"type":"object"}`)
+			},
+			false,
+		},
+		{"description changed", func(t *model.Tool) { t.Description = "adds numbers" }, true},
+		{
+			"schema changed",
+			func(t *model.Tool) {
+				t.InputSchema = []byte(`{"type": "object", "properties": {"a": {"type": "number"}}}`)
+			},
+			true,
+		},
+		{"annotations changed", func(t *model.Tool) { t.Annotations = []byte(`{"readOnlyHint": false}`) }, true},
+		{"annotations removed", func(t *model.Tool) { t.Annotations = nil }, true},
+		{"title changed", func(t *model.Tool) { t.Title = "Add" }, true},
+		{"output schema added", func(t *model.Tool) { t.OutputSchema = []byte(`{"type": "object"}`) }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := base
+			tt.modify(&changed)
+			if got := toolChanged(&base, &changed); got != tt.want {
+				t.Errorf("toolChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncDropsConflictingAlias(t *testing.T) {
+	m := newTestMCPService(t)
+	s := &model.McpServer{Name: "srv", Transport: model.TransportStreamableHTTP, URL: "http://127.0.0.1:1/mcp"}
+	if err := m.db.Create(s).Error; err != nil {
+		t.Fatalf("failed to create MCP server: %v", err)
+	}
+	if _, err := m.syncServerTools(s, testUpstreamTools("search"), nil); err != nil {
+		t.Fatalf("syncServerTools() error = %v", err)
+	}
+	alias := "find"
+	if _, err := m.UpdateToolOverrides("srv/search", &types.ToolOverridesUpdate{Alias: &alias}); err != nil {
+		t.Fatalf("UpdateToolOverrides() error = %v", err)
+	}
+
+	// the server now provides a tool named like the alias
+	result, err := m.syncServerTools(s, testUpstreamTools("search", "find"), nil)
+	if err != nil {
+		t.Fatalf("syncServerTools() error = %v", err)
+	}
+	if len(result.Added) != 1 || result.Added[0] != "srv/find" {
+		t.Errorf("added = %v, want [srv/find]", result.Added)
+	}
+
+	var tool model.Tool
+	if err := m.db.Where("server_id = ? AND name = ?", s.ID, "search").First(&tool).Error; err != nil {
+		t.Fatalf("failed to get tool: %v", err)
+	}
+	if tool.Overrides.Alias != "" {
+		t.Errorf("alias = %q, want it dropped", tool.Overrides.Alias)
+	}
+
+	c := newInProcessClient(t, m.mcpProxyServer)
+	resp, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	var names []string
+	for _, tool := range resp.Tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "srv/find" || names[1] != "srv/search" {
+		t.Errorf("proxy tools = %v, want [srv/find srv/search]", names)
+	}
+}
+
+func TestServerLockSet(t *testing.T) {
+	ls := newServerLockSet()
+	unlockA := ls.lock("a")
+
+	// another server can be locked while the first one is
+	done := make(chan struct{})
+	go func() {
+		ls.lock("b")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("locking server b waited for server a")
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		unlock := ls.lock("a")
+		close(locked)
+		unlock()
+	}()
+	select {
+	case <-locked:
+		t.Fatal("server a was locked twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlockA()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server a could not be locked after it was unlocked")
+	}
+
+	// wait for the second lock of server a to be released
+	for i := 0; ; i++ {
+		ls.mu.Lock()
+		n := len(ls.locks)
+		ls.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("%d locks are left once all were released, want none", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
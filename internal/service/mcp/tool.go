@@ -8,6 +8,8 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/types"
+	"gorm.io/datatypes"
+	"log"
 )
 
 // ListTools returns all tools registered in the registry.
@@ -16,13 +18,17 @@ func (m *MCPService) ListTools() ([]model.Tool, error) {
 	if err := m.db.Find(&tools).Error; err != nil {
 		return nil, err
 	}
-	// prepend server name to tool names to ensure we only return the unique names of tools to user
+	// return the tools as they are exposed, with their unique names (prefixed with the server name) and overrides
 	for i := range tools {
 		var s model.McpServer
 		if err := m.db.First(&s, "id = ?", tools[i].ServerID).Error; err != nil {
 			return nil, fmt.Errorf("failed to get server for tool %s: %w", tools[i].Name, err)
 		}
-		tools[i].Name = mergeServerToolNames(s.Name, tools[i].Name)
+		t, err := applyToolOverrides(s.Name, &tools[i])
+		if err != nil {
+			return nil, err
+		}
+		tools[i] = *t
 	}
 	return tools, nil
 }
@@ -43,18 +49,55 @@ func (m *MCPService) ListToolsByServer(name string) ([]model.Tool, error) {
 		return nil, fmt.Errorf("failed to get tools for server %s from DB: %w", name, err)
 	}
 
-	// prepend server name to tool names to ensure we only return the unique names of tools to user
+	// return the tools as they are exposed, with their unique names (prefixed with the server name) and overrides
 	for i := range tools {
-		tools[i].Name = mergeServerToolNames(s.Name, tools[i].Name)
+		t, err := applyToolOverrides(s.Name, &tools[i])
+		if err != nil {
+			return nil, err
+		}
+		tools[i] = *t
 	}
 
 	return tools, nil
 }
 
-func (m *MCPService) GetTool(name string) (*model.Tool, error) {
+// resolveToolName returns the names of the MCP server and of the tool exposed with the given name,
+// ie- the name the MCP server knows the tool by.
+// Tools exposed with an alias are looked up by their alias. Names shortened to fit the maximum length
+// of tool names can't be split back into the tool name, so the tool is looked up among the server's tools instead.
+func (m *MCPService) resolveToolName(name string) (string, string, error) {
 	serverName, toolName, ok := splitServerToolName(name)
 	if !ok {
-		return nil, fmt.Errorf("invalid input: tool name does not contain a %s separator", serverToolNameSep)
+		return "", "", fmt.Errorf("invalid input: tool name does not contain a %s separator", toolNames.separator)
+	}
+	s, err := m.GetMcpServer(serverName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get MCP server %s from DB: %w", serverName, err)
+	}
+	if !isShortenedToolName(name) {
+		var tool model.Tool
+		if m.db.Where("server_id = ? AND override_alias = ?", s.ID, toolName).First(&tool).Error == nil {
+			return serverName, tool.Name, nil
+		}
+		return serverName, toolName, nil
+	}
+	var tools []model.Tool
+	if err := m.db.Where("server_id = ?", s.ID).Find(&tools).Error; err != nil {
+		return "", "", fmt.Errorf("failed to get tools for server %s from DB: %w", serverName, err)
+	}
+	for i := range tools {
+		if exposedToolName(serverName, &tools[i]) == name {
+			return serverName, tools[i].Name, nil
+		}
+	}
+	// the name wasn't shortened after all, eg- the tool isn't registered (yet)
+	return serverName, toolName, nil
+}
+
+func (m *MCPService) GetTool(name string) (*model.Tool, error) {
+	serverName, toolName, err := m.resolveToolName(name)
+	if err != nil {
+		return nil, err
 	}
 
 	s, err := m.GetMcpServer(serverName)
@@ -66,16 +109,57 @@ func (m *MCPService) GetTool(name string) (*model.Tool, error) {
 	if err := m.db.Where("server_id = ? AND name = ?", s.ID, toolName).First(&tool).Error; err != nil {
 		return nil, fmt.Errorf("failed to get tool %s from DB: %w", name, err)
 	}
-	// set the tool name back to the full name including server name
-	tool.Name = name
-	return &tool, nil
+	return applyToolOverrides(s.Name, &tool)
+}
+
+// EnableTool makes a disabled tool available to MCP clients again and returns its definition.
+func (m *MCPService) EnableTool(name string) (*model.Tool, error) {
+	return m.setToolEnabled(name, true)
+}
+
+// DisableTool hides a tool from MCP clients and rejects its calls, without deregistering its MCP server.
+// It returns the tool's definition.
+func (m *MCPService) DisableTool(name string) (*model.Tool, error) {
+	return m.setToolEnabled(name, false)
+}
+
+func (m *MCPService) setToolEnabled(name string, enabled bool) (*model.Tool, error) {
+	serverName, toolName, err := m.resolveToolName(name)
+	if err != nil {
+		return nil, err
+	}
+	s, err := m.GetMcpServer(serverName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP server %s from DB: %w", serverName, err)
+	}
+	var tool model.Tool
+	if err := m.db.Where("server_id = ? AND name = ?", s.ID, toolName).First(&tool).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tool %s from DB: %w", name, err)
+	}
+	if err := m.db.Model(&tool).Update("enabled", enabled).Error; err != nil {
+		return nil, fmt.Errorf("failed to update tool %s: %w", name, err)
+	}
+	tool.Enabled = enabled
+
+	if enabled {
+		if err := m.addProxyTool(s, &tool); err != nil {
+			return nil, err
+		}
+	} else {
+		m.mcpProxyServer.DeleteTools(exposedToolName(s.Name, &tool))
+	}
+	return applyToolOverrides(s.Name, &tool)
 }
 
 // InvokeTool invokes a tool from a registered MCP server and returns its response.
+// If the tool has an output schema, the structured content it returns is validated against it
+// and the result carries a warning if it doesn't match.
+// See validateOutputSchema for how the schema is interpreted.
+// Calls to disabled tools are rejected.
 func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[string]any) (*types.ToolInvokeResult, error) {
-	serverName, toolName, ok := splitServerToolName(name)
-	if !ok {
-		return nil, fmt.Errorf("invalid input: tool name does not contain a %s separator", serverToolNameSep)
+	serverName, toolName, err := m.resolveToolName(name)
+	if err != nil {
+		return nil, err
 	}
 	serverModel, err := m.GetMcpServer(serverName)
 	if err != nil {
@@ -85,88 +169,163 @@ func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[strin
 			err,
 		)
 	}
-
-	mcpClient, err := createMcpServerConn(ctx, serverModel)
-	if err != nil {
-		return nil, fmt.Errorf(
-			"failed to create connection to MCP server %s: %w", serverName, err,
-		)
-	}
-	defer mcpClient.Close()
-
-	callToolReq := mcp.CallToolRequest{}
-	callToolReq.Params.Name = toolName
-	callToolReq.Params.Arguments = args
-
-	callToolResp, err := mcpClient.CallTool(ctx, callToolReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call tool %s on MCP server %s: %w", toolName, serverName, err)
+	// the tool may not be registered yet (eg- if the server added it without notifying),
+	// it is called anyway but its result can't be validated
+	var tool model.Tool
+	registered := m.db.Where("server_id = ? AND name = ?", serverModel.ID, toolName).First(&tool).Error == nil
+	if registered && !tool.Enabled {
+		return nil, fmt.Errorf("tool %s is disabled", name)
 	}
 
-	// NOTE: callToolResp.Content is a list of Content objects.
-	// If the tool returns a list as its result, it gets converted to a list of Content objects.
-	// But if the tool returns any other type of object (string, map, number, etc), then it is
-	// completely available in Content[0].
+	request := mcp.CallToolRequest{}
+	request.Params.Name = toolName
+	request.Params.Arguments = args
 
-	// Convert the Content field from []mcp.Content to []map[string]any to pass downstream.
-	// We don't attempt to cast the data into specific types because this method should simply
+	// NOTE: We don't attempt to keep the content items as specific types because this method should simply
 	// forward the tool's response to the client.
 	// It is up to the client of this API to convert the data into specific types like
 	// Text, Image, etc.
-	contentList := make([]map[string]any, 0, len(callToolResp.Content))
-	for _, item := range callToolResp.Content {
-		var m map[string]any
-		serialized, err := json.Marshal(item)
+	var result *types.ToolInvokeResult
+	// only calls to read-only tools are safe to retry
+	retryable := registered && tool.IsReadOnly()
+	err = m.callMcpServer(ctx, serverModel, retryable, func(ctx context.Context, c *client.Client) error {
+		resp, err := c.CallTool(ctx, request)
+		if err != nil {
+			return err
+		}
+		raw, err := json.Marshal(resp)
 		if err != nil {
-			// TODO
-			continue
+			return fmt.Errorf("failed to encode the result: %w", err)
 		}
-		if err = json.Unmarshal(serialized, &m); err != nil {
-			// TODO
-			continue
+		result = &types.ToolInvokeResult{}
+		if err := json.Unmarshal(raw, result); err != nil {
+			return fmt.Errorf("failed to decode the result: %w", err)
 		}
-		contentList = append(contentList, m)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call tool %s on MCP server %s: %w", toolName, serverName, err)
+	}
+	if result.Content == nil {
+		result.Content = []map[string]any{}
 	}
 
-	result := &types.ToolInvokeResult{
-		Meta:    callToolResp.Meta,
-		IsError: callToolResp.IsError,
-		Content: contentList,
+	// servers must return structured content conforming to the output schema, unless the call failed.
+	// The result is still returned if they don't, the caller decides whether it is usable.
+	if registered && len(tool.OutputSchema) > 0 && !result.IsError {
+		if result.StructuredContent == nil {
+			result.Warning = fmt.Sprintf("tool %s has an output schema but returned no structured content", name)
+		} else if err := validateOutputSchema(tool.OutputSchema, result.StructuredContent); err != nil {
+			result.Warning = fmt.Sprintf("structured content returned by tool %s does not match its output schema: %v", name, err)
+		}
+		if result.Warning != "" {
+			log.Printf("[call] %s", result.Warning)
+		}
 	}
 	return result, nil
 }
 
-// registerServerTools fetches all tools from an MCP server and registers them in the DB.
-func (m *MCPService) registerServerTools(ctx context.Context, s *model.McpServer, c *client.Client) error {
-	// fetch all tools from the server so they can be added to the DB
-	resp, err := c.ListTools(ctx, mcp.ListToolsRequest{})
-	if err != nil {
-		return fmt.Errorf("failed to fetch tools from MCP server %s: %w", s.Name, err)
-	}
-	for _, tool := range resp.Tools {
-		// extracting json schema is currently on best-effort basis
-		// if it fails, we log the error and continue with the next tool
-		jsonSchema, _ := json.Marshal(tool.InputSchema)
+// upstreamTool is the definition of a tool as provided by an upstream MCP server.
+// mcp.Tool doesn't carry all the fields of the MCP spec (eg- title) and only keeps the basic parts
+// of the input and output schemas, so tools are decoded from the raw result of tools/list instead.
+type upstreamTool struct {
+	Name         string             `json:"name"`
+	Title        string             `json:"title,omitempty"`
+	Description  string             `json:"description,omitempty"`
+	InputSchema  json.RawMessage    `json:"inputSchema"`
+	OutputSchema json.RawMessage    `json:"outputSchema,omitempty"`
+	Annotations  mcp.ToolAnnotation `json:"annotations"`
+}
 
-		t := &model.Tool{
-			ServerID:    s.ID,
-			Name:        tool.GetName(),
-			Description: tool.Description,
-			InputSchema: jsonSchema,
+// fetchServerTools lists the full definitions of the tools provided by an MCP server.
+func fetchServerTools(ctx context.Context, c *client.Client) ([]upstreamTool, error) {
+	var tools []upstreamTool
+	request := mcp.ListToolsRequest{}
+	for {
+		var raw json.RawMessage
+		result, err := c.ListToolsByPage(withRawResult(ctx, &raw), request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		if raw == nil {
+			// the raw result is not available, the definitions are limited to what mcp-go decoded
+			if raw, err = json.Marshal(result); err != nil {
+				return nil, fmt.Errorf("failed to encode the list of tools: %w", err)
+			}
+		}
+		var page struct {
+			Tools []upstreamTool `json:"tools"`
 		}
-		if err := m.db.Create(t).Error; err != nil {
-			// TODO: Add error log about this failure
-			// If registration of a tool fails, we should not fail the entire server registration.
-			// Instead, continue with the next tool.
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return nil, fmt.Errorf("failed to decode the list of tools: %w", err)
+		}
+		tools = append(tools, page.Tools...)
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		request.Params.Cursor = result.NextCursor
+	}
+}
+
+// newToolModel converts a tool provided by an MCP server into its DB model.
+func newToolModel(s *model.McpServer, tool upstreamTool) *model.Tool {
+	// extracting annotations is currently on best-effort basis
+	annotations, _ := json.Marshal(tool.Annotations)
+
+	t := &model.Tool{
+		ServerID:    s.ID,
+		Enabled:     true,
+		Name:        tool.Name,
+		Title:       tool.Title,
+		Description: tool.Description,
+		InputSchema: datatypes.JSON(tool.InputSchema),
+		Annotations: annotations,
+	}
+	if len(tool.OutputSchema) > 0 && string(tool.OutputSchema) != "null" {
+		t.OutputSchema = datatypes.JSON(tool.OutputSchema)
+	}
+	return t
+}
 
-			//fmt.Printf("failed to register tool %s in DB: %w", mergeServerToolNames(s.Name, t.Name), err)
-		} else {
-			// Set tool name to include the server name prefix to make it recognizable by MCPJungle
-			tool.Name = mergeServerToolNames(s.Name, tool.Name)
-			// add the tool to the MCP proxy server
-			m.mcpProxyServer.AddTool(tool, m.mcpProxyToolCallHandler)
+// newProxyTool converts a tool stored in the DB into its definition in the MCP proxy server.
+// The name of the tool must already be prefixed with the server name.
+// mcp-go only supports the title as an annotation, so the title is sent that way unless the annotations already have one.
+func newProxyTool(t *model.Tool) (mcp.Tool, error) {
+	tool := mcp.Tool{Name: t.Name, Description: t.Description}
+	if len(t.InputSchema) > 0 && string(t.InputSchema) != "null" {
+		tool.RawInputSchema = json.RawMessage(t.InputSchema)
+	} else {
+		tool.InputSchema = mcp.ToolInputSchema{Type: "object"}
+	}
+	if len(t.OutputSchema) > 0 {
+		tool.RawOutputSchema = json.RawMessage(t.OutputSchema)
+	}
+	if len(t.Annotations) > 0 {
+		if err := json.Unmarshal(t.Annotations, &tool.Annotations); err != nil {
+			return tool, fmt.Errorf("failed to unmarshal annotations %s for tool %s: %w", t.Annotations, t.Name, err)
 		}
 	}
+	if tool.Annotations.Title == "" {
+		tool.Annotations.Title = t.Title
+	}
+	return tool, nil
+}
+
+// addProxyTool adds a tool of an MCP server, or replaces its definition, in the MCP proxy server.
+// The tool is exposed with its overrides. Disabled tools are not added.
+func (m *MCPService) addProxyTool(s *model.McpServer, t *model.Tool) error {
+	if !t.Enabled {
+		return nil
+	}
+	proxied, err := applyToolOverrides(s.Name, t)
+	if err != nil {
+		return err
+	}
+	tool, err := newProxyTool(proxied)
+	if err != nil {
+		return err
+	}
+	m.mcpProxyServer.AddTool(tool, m.mcpProxyToolCallHandler)
 	return nil
 }
 
@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewProxyTool(t *testing.T) {
+	tests := []struct {
+		name      string
+		tool      model.Tool
+		wantTitle string
+		wantInput string
+	}{
+		{
+			name: "keeps the full input schema",
+			tool: model.Tool{
+				Name:        "db/query",
+				InputSchema: []byte(`{"type":"object","properties":{"sql":{"type":"string"}},"additionalProperties":false}`),
+			},
+			wantInput: `{"type":"object","properties":{"sql":{"type":"string"}},"additionalProperties":false}`,
+		},
+		{
+			name:      "tool without input schema",
+			tool:      model.Tool{Name: "time/now"},
+			wantInput: `{"type":"object"}`,
+		},
+		{
+			name: "title is sent as an annotation",
+			tool: model.Tool{
+				Name:        "db/query",
+				Title:       "Run query",
+				InputSchema: []byte(`{"type":"object"}`),
+				Annotations: []byte(`{"readOnlyHint":true}`),
+			},
+			wantTitle: "Run query",
+			wantInput: `{"type":"object"}`,
+		},
+		{
+			name: "title annotation takes precedence",
+			tool: model.Tool{
+				Name:        "db/query",
+				Title:       "Run query",
+				InputSchema: []byte(`{"type":"object"}`),
+				Annotations: []byte(`{"title":"Query the database"}`),
+			},
+			wantTitle: "Query the database",
+			wantInput: `{"type":"object"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, err := newProxyTool(&tt.tool)
+			if err != nil {
+				t.Fatalf("newProxyTool() error = %v", err)
+			}
+			if tool.Annotations.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", tool.Annotations.Title, tt.wantTitle)
+			}
+			b, err := json.Marshal(tool)
+			if err != nil {
+				t.Fatalf("failed to marshal tool: %v", err)
+			}
+			var got struct {
+				InputSchema json.RawMessage `json:"inputSchema"`
+			}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("failed to unmarshal tool: %v", err)
+			}
+			if !jsonEqual(got.InputSchema, []byte(tt.wantInput)) {
+				t.Errorf("input schema = %s, want %s", got.InputSchema, tt.wantInput)
+			}
+		})
+	}
+}
+
+func TestInvokeToolOutputSchemaMismatch(t *testing.T) {
+	upstream := server.NewMCPServer("upstream", "0.0.0", server.WithToolCapabilities(false))
+	upstream.AddTool(mcp.NewTool("count"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("three"), nil
+	})
+	srv := server.NewTestStreamableHTTPServer(upstream)
+	t.Cleanup(srv.Close)
+
+	m := newTestMCPService(t)
+	s := &model.McpServer{Name: "upstream", Transport: model.TransportStreamableHTTP, URL: srv.URL + "/mcp"}
+	if err := m.db.Create(s).Error; err != nil {
+		t.Fatalf("failed to create MCP server: %v", err)
+	}
+	tools := testUpstreamTools("count")
+	tools[0].OutputSchema = json.RawMessage(`{"type": "object", "properties": {"n": {"type": "integer"}}}`)
+	if _, err := m.syncServerTools(s, tools, nil); err != nil {
+		t.Fatalf("syncServerTools() error = %v", err)
+	}
+
+	result, err := m.InvokeTool(context.Background(), "upstream/count", nil)
+	if err != nil {
+		t.Fatalf("InvokeTool() error = %v", err)
+	}
+	if len(result.Content) != 1 || result.Content[0]["text"] != "three" {
+		t.Errorf("content = %v, want the content returned by the tool", result.Content)
+	}
+	if !strings.Contains(result.Warning, "returned no structured content") {
+		t.Errorf("warning = %q, want it to report the missing structured content", result.Warning)
+	}
+}
+
+func TestProxyToolsListOutputSchema(t *testing.T) {
+	m := newTestMCPService(t)
+	s := &model.McpServer{Name: "upstream", Transport: model.TransportStreamableHTTP, URL: "http://127.0.0.1:1/mcp"}
+	if err := m.db.Create(s).Error; err != nil {
+		t.Fatalf("failed to create MCP server: %v", err)
+	}
+	tools := testUpstreamTools("count")
+	tools[0].OutputSchema = json.RawMessage(`{"type": "object", "properties": {"n": {"type": "integer"}}}`)
+	if _, err := m.syncServerTools(s, tools, nil); err != nil {
+		t.Fatalf("syncServerTools() error = %v", err)
+	}
+	srv := server.NewTestStreamableHTTPServer(m.mcpProxyServer)
+	t.Cleanup(srv.Close)
+
+	post := func(sessionID, body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if sessionID != "" {
+			req.Header.Set(server.HeaderKeySessionID, sessionID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+	resp := post("", `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {
+		"protocolVersion": "2025-06-18", "capabilities": {}, "clientInfo": {"name": "test", "version": "1.0.0"}
+	}}`)
+	sessionID := resp.Header.Get(server.HeaderKeySessionID)
+
+	resp = post(sessionID, `{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`)
+	var list struct {
+		Result struct {
+			Tools []struct {
+				Name         string          `json:"name"`
+				OutputSchema json.RawMessage `json:"outputSchema"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode tools/list response: %v", err)
+	}
+	if len(list.Result.Tools) != 1 {
+		t.Fatalf("tools/list returned %d tools, want 1", len(list.Result.Tools))
+	}
+	if got := list.Result.Tools[0].OutputSchema; !jsonEqual(got, tools[0].OutputSchema) {
+		t.Errorf("output schema = %s, want %s", got, tools[0].OutputSchema)
+	}
+}
+
+func TestFetchServerToolsKeepsFullDefinitions(t *testing.T) {
+	inputSchema := json.RawMessage(`{"type": "object", "properties": {"sql": {"type": "string"}}, "additionalProperties": false}`)
+	upstream := server.NewMCPServer("upstream", "0.0.0", server.WithToolCapabilities(false))
+	upstream.AddTool(
+		mcp.NewToolWithRawSchema("query", "Runs a query", inputSchema),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		},
+	)
+	srv := server.NewTestStreamableHTTPServer(upstream)
+	t.Cleanup(srv.Close)
+
+	s := &model.McpServer{Name: "upstream", Transport: model.TransportStreamableHTTP, URL: srv.URL + "/mcp"}
+	c, _, err := createMcpServerConn(context.Background(), s, nil, false)
+	if err != nil {
+		t.Fatalf("createMcpServerConn() error = %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	tools, err := fetchServerTools(context.Background(), c)
+	if err != nil {
+		t.Fatalf("fetchServerTools() error = %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name != "query" {
+		t.Fatalf("fetchServerTools() = %+v, want the query tool", tools)
+	}
+	if !jsonEqual(tools[0].InputSchema, inputSchema) {
+		t.Errorf("input schema = %s, want %s", tools[0].InputSchema, inputSchema)
+	}
+}
@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"regexp"
+	"strings"
+)
+
+// toolsetEntrySeparator separates the server and tool patterns of the toolset entries stored in the DB.
+// Entries are written with the configured tool name separator, but stored with this one,
+// so that they keep selecting the same tools if the tool name separator changes.
+// Server names never contain it, so the entries can always be split back.
+const toolsetEntrySeparator = "/"
+
+// CreateToolset validates a toolset and saves it in the DB.
+// Its MCP server is available at `/mcp/<name>` right away.
+func (m *MCPService) CreateToolset(t *model.Toolset) error {
+	if err := validateToolsetName(t.Name); err != nil {
+		return err
+	}
+	entries, err := toolsetEntries(t)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("toolset %s must select at least one tool", t.Name)
+	}
+	for i, e := range entries {
+		if err := validateToolsetEntry(e); err != nil {
+			return err
+		}
+		entries[i] = convertToolsetEntry(e, toolNames.separator, toolsetEntrySeparator)
+	}
+	if t.Tools, err = json.Marshal(entries); err != nil {
+		return fmt.Errorf("failed to marshal tools of toolset %s: %w", t.Name, err)
+	}
+	if err := m.db.Create(t).Error; err != nil {
+		return fmt.Errorf("failed to create toolset %s: %w", t.Name, err)
+	}
+	return displayToolsetEntries(t)
+}
+
+// ListToolsets returns all toolsets.
+func (m *MCPService) ListToolsets() ([]model.Toolset, error) {
+	var toolsets []model.Toolset
+	if err := m.db.Order("name").Find(&toolsets).Error; err != nil {
+		return nil, err
+	}
+	for i := range toolsets {
+		if err := displayToolsetEntries(&toolsets[i]); err != nil {
+			return nil, err
+		}
+	}
+	return toolsets, nil
+}
+
+// GetToolset returns the toolset with the given name.
+func (m *MCPService) GetToolset(name string) (*model.Toolset, error) {
+	var t model.Toolset
+	if err := m.db.Where("name = ?", name).First(&t).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("toolset %s not found", name)
+		}
+		return nil, fmt.Errorf("failed to get toolset %s from DB: %w", name, err)
+	}
+	if err := displayToolsetEntries(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeleteToolset deletes a toolset. Its MCP server stops being available immediately.
+// Deleting a toolset that does not exist is not an error.
+func (m *MCPService) DeleteToolset(name string) error {
+	return m.db.Unscoped().Where("name = ?", name).Delete(&model.Toolset{}).Error
+}
+
+// validateToolsetName checks that the toolset name can be used in the path of its MCP server.
+func validateToolsetName(name string) error {
+	if !validServerName.MatchString(name) {
+		return fmt.Errorf("invalid toolset name: '%s' must not contain slashes or special characters", name)
+	}
+	return nil
+}
+
+// validateToolsetEntry checks that an entry of a toolset can select tools.
+func validateToolsetEntry(entry string) error {
+	serverPattern, _, _ := strings.Cut(entry, toolNames.separator)
+	if strings.TrimSpace(serverPattern) == "" {
+		return fmt.Errorf("invalid toolset entry '%s': it must start with a server name or pattern", entry)
+	}
+	return nil
+}
+
+// convertToolsetEntry replaces the separator between the server and tool patterns of a toolset entry.
+func convertToolsetEntry(entry, from, to string) string {
+	serverPattern, toolPattern, hasTool := strings.Cut(strings.TrimSpace(entry), from)
+	if !hasTool {
+		return serverPattern
+	}
+	return serverPattern + to + toolPattern
+}
+
+// displayToolsetEntries converts the stored entries of a toolset to the configured tool name separator,
+// so that they read like the names of the tools they select.
+func displayToolsetEntries(t *model.Toolset) error {
+	if toolNames.separator == toolsetEntrySeparator {
+		return nil
+	}
+	entries, err := toolsetEntries(t)
+	if err != nil {
+		return err
+	}
+	for i, e := range entries {
+		entries[i] = convertToolsetEntry(e, toolsetEntrySeparator, toolNames.separator)
+	}
+	t.Tools, err = json.Marshal(entries)
+	return err
+}
+
+// toolsetEntries decodes the entries of a toolset.
+func toolsetEntries(t *model.Toolset) ([]string, error) {
+	if len(t.Tools) == 0 {
+		return nil, nil
+	}
+	var entries []string
+	if err := json.Unmarshal(t.Tools, &entries); err != nil {
+		return nil, fmt.Errorf("invalid tools for toolset %s, a list of tool names is expected: %w", t.Name, err)
+	}
+	return entries, nil
+}
+
+// toolsetMatcher tells whether a tool is part of a toolset.
+type toolsetMatcher struct {
+	patterns []toolsetPattern
+}
+
+// toolsetPattern is an entry of a toolset, split into the patterns of the server and tool names.
+type toolsetPattern struct {
+	server *regexp.Regexp
+	// tool is nil if the entry selects all the tools of the matching servers
+	tool *regexp.Regexp
+}
+
+// newToolsetMatcher returns the matcher of a toolset whose entries use the configured tool name separator,
+// as returned by GetToolset.
+func newToolsetMatcher(t *model.Toolset) (*toolsetMatcher, error) {
+	entries, err := toolsetEntries(t)
+	if err != nil {
+		return nil, err
+	}
+	m := &toolsetMatcher{patterns: make([]toolsetPattern, 0, len(entries))}
+	for _, e := range entries {
+		serverPattern, toolPattern, hasTool := strings.Cut(e, toolNames.separator)
+		p := toolsetPattern{server: globToRegexp(serverPattern)}
+		if hasTool {
+			p.tool = globToRegexp(toolPattern)
+		}
+		m.patterns = append(m.patterns, p)
+	}
+	return m, nil
+}
+
+// matches returns true if the given tool of an MCP server is part of the toolset.
+// The tool is identified by the name the MCP server knows it by, not the name it is exposed with,
+// so that toolsets keep selecting the same tools regardless of aliases and of how tool names are formatted.
+func (m *toolsetMatcher) matches(serverName, toolName string) bool {
+	for _, p := range m.patterns {
+		if p.server.MatchString(serverName) && (p.tool == nil || p.tool.MatchString(toolName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp compiles a pattern in which `*` matches any sequence of characters.
+func globToRegexp(glob string) *regexp.Regexp {
+	parts := strings.Split(strings.TrimSpace(glob), "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// toolsetFromContext returns the toolset whose MCP server received the request in ctx,
+// or nil if the request was sent to the main MCP proxy server.
+func toolsetFromContext(ctx context.Context) *model.Toolset {
+	t, _ := ctx.Value("toolset").(*model.Toolset)
+	return t
+}
+
+// checkToolsetAccess returns an error if the request in ctx was sent to the MCP server of a toolset
+// that doesn't include the given tool of an MCP server.
+func checkToolsetAccess(ctx context.Context, serverName, toolName string) error {
+	t := toolsetFromContext(ctx)
+	if t == nil {
+		return nil
+	}
+	matcher, err := newToolsetMatcher(t)
+	if err != nil {
+		return err
+	}
+	if !matcher.matches(serverName, toolName) {
+		return fmt.Errorf("tool %s is not part of toolset %s", joinServerName(serverName, toolName), t.Name)
+	}
+	return nil
+}
+
+// toolRef identifies a tool by the MCP server that provides it and the name the server knows it by.
+type toolRef struct {
+	server string
+	tool   string
+}
+
+// exposedToolRefs returns all the tools in the registry by the name they are exposed with.
+func (m *MCPService) exposedToolRefs() (map[string]toolRef, error) {
+	var servers []model.McpServer
+	if err := m.db.Find(&servers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list MCP servers from DB: %w", err)
+	}
+	serverNames := make(map[uint]string, len(servers))
+	for _, s := range servers {
+		serverNames[s.ID] = s.Name
+	}
+	var tools []model.Tool
+	if err := m.db.Find(&tools).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tools from DB: %w", err)
+	}
+	refs := make(map[string]toolRef, len(tools))
+	for i := range tools {
+		serverName := serverNames[tools[i].ServerID]
+		refs[exposedToolName(serverName, &tools[i])] = toolRef{server: serverName, tool: tools[i].Name}
+	}
+	return refs, nil
+}
+
+// checkNotInToolset returns an error if the request in ctx was sent to the MCP server of a toolset,
+// which only exposes tools, not the given kind of items (eg- prompts).
+func checkNotInToolset(ctx context.Context, kind string) error {
+	if t := toolsetFromContext(ctx); t != nil {
+		return fmt.Errorf("%s are not available through toolset %s", kind, t.Name)
+	}
+	return nil
+}
+
+// applyToolsetInfo makes the result of the initialization of a session describe the MCP server of the toolset
+// the request in ctx was sent to, if any.
+func applyToolsetInfo(ctx context.Context, result *mcp.InitializeResult) {
+	t := toolsetFromContext(ctx)
+	if t == nil {
+		return
+	}
+	result.ServerInfo.Name = t.Name
+	result.Instructions = t.Instructions
+	result.Capabilities.Prompts = nil
+	result.Capabilities.Resources = nil
+}
@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/types"
+	"testing"
+)
+
+func TestToolsetMatcher(t *testing.T) {
+	toolset := &model.Toolset{
+		Name:  "coding",
+		Tools: []byte(`["github/*", "filesystem/read_*", "time", "calc*/add"]`),
+	}
+	m, err := newToolsetMatcher(toolset)
+	if err != nil {
+		t.Fatalf("newToolsetMatcher() error = %v", err)
+	}
+
+	tests := []struct {
+		server string
+		tool   string
+		want   bool
+	}{
+		{"github", "git_commit", true},
+		{"filesystem", "read_file", true},
+		{"filesystem", "write_file", false},
+		{"time", "get_current_time", true},
+		{"calculator", "add", true},
+		{"calculator", "multiply", false},
+		{"gitlab", "git_commit", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.server+"/"+tt.tool, func(t *testing.T) {
+			if got := m.matches(tt.server, tt.tool); got != tt.want {
+				t.Errorf("matches(%q, %q) = %v, want %v", tt.server, tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolsetSurvivesSeparatorChange(t *testing.T) {
+	defer func(f toolNameFormat) { toolNames = f }(toolNames)
+	m := newTestMCPService(t)
+	if err := m.CreateToolset(&model.Toolset{Name: "coding", Tools: []byte(`["github/git_*"]`)}); err != nil {
+		t.Fatalf("CreateToolset() error = %v", err)
+	}
+
+	if err := ConfigureToolNames("__", 64); err != nil {
+		t.Fatalf("ConfigureToolNames() error = %v", err)
+	}
+	toolset, err := m.GetToolset("coding")
+	if err != nil {
+		t.Fatalf("GetToolset() error = %v", err)
+	}
+	if string(toolset.Tools) != `["github__git_*"]` {
+		t.Errorf("tools = %s, want the entries to use the new separator", toolset.Tools)
+	}
+	matcher, err := newToolsetMatcher(toolset)
+	if err != nil {
+		t.Fatalf("newToolsetMatcher() error = %v", err)
+	}
+	if !matcher.matches("github", "git_commit") {
+		t.Error("toolset no longer selects github's git_commit after the separator changed")
+	}
+}
+
+func TestValidateToolsetEntry(t *testing.T) {
+	for _, e := range []string{"github", "github/*", "*/read_file"} {
+		if err := validateToolsetEntry(e); err != nil {
+			t.Errorf("validateToolsetEntry(%q) error = %v, want nil", e, err)
+		}
+	}
+	for _, e := range []string{"", "/read_file", " /x"} {
+		if err := validateToolsetEntry(e); err == nil {
+			t.Errorf("validateToolsetEntry(%q) error = nil, want an error", e)
+		}
+	}
+}
+
+func TestExposedToolRefs(t *testing.T) {
+	m := newTestMCPService(t)
+	s := &model.McpServer{Name: "github", Transport: model.TransportStreamableHTTP, URL: "http://127.0.0.1:1/mcp"}
+	if err := m.db.Create(s).Error; err != nil {
+		t.Fatalf("failed to create MCP server: %v", err)
+	}
+	if _, err := m.syncServerTools(s, testUpstreamTools("search_code"), nil); err != nil {
+		t.Fatalf("syncServerTools() error = %v", err)
+	}
+	alias := "find"
+	if _, err := m.UpdateToolOverrides("github/search_code", &types.ToolOverridesUpdate{Alias: &alias}); err != nil {
+		t.Fatalf("UpdateToolOverrides() error = %v", err)
+	}
+
+	refs, err := m.exposedToolRefs()
+	if err != nil {
+		t.Fatalf("exposedToolRefs() error = %v", err)
+	}
+	// a toolset selecting github/search_code must still select the tool exposed as github/find
+	want := toolRef{server: "github", tool: "search_code"}
+	if got := refs["github/find"]; got != want {
+		t.Errorf("exposedToolRefs()[github/find] = %+v, want %+v", got, want)
+	}
+}
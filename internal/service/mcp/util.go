@@ -2,6 +2,11 @@ package mcp
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/mark3labs/mcp-go/client"
@@ -9,17 +14,43 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"net"
+	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
 	"syscall"
+	"unicode/utf8"
 )
 
-const serverToolNameSep = "/"
+// DefaultToolNameSeparator separates the server name from the tool name in the names tools are exposed with,
+// unless another separator is configured.
+const DefaultToolNameSeparator = "/"
+
+// shortenedToolNameHashLen is the number of hex characters of the hash that ends shortened tool names
+const shortenedToolNameHashLen = 8
+
+// MinToolNameMaxLength is the smallest limit that can be put on the length of the names tools are exposed with
+const MinToolNameMaxLength = 16
+
+// proxyResourceURIPrefix is prepended, along with the server name, to the URIs of upstream resources,
+// eg- the resource `file:///README.md` of the server `docs` is exposed as `mcpjungle://docs/file:///README.md`.
+const proxyResourceURIPrefix = "mcpjungle://"
+
+// proxyResourceURITemplate matches the URIs of all upstream resources as exposed by the MCP proxy server.
+const proxyResourceURITemplate = proxyResourceURIPrefix + "{server}/{+uri}"
 
 // Only allow letters, numbers, hyphens, and underscores
 var validServerName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 
+// invalidServerNameChars matches runs of characters that are not allowed in a server name
+var invalidServerNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// validToolAlias matches the characters the MCP spec allows in tool names
+var validToolAlias = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// validHeaderName matches the characters allowed in an HTTP header field name (RFC 9110 token)
+var validHeaderName = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
 // validServerName checks if the server name is valid.
 // Server name must not contain slashes '/'
 // Tools in mcpjungle are identified by `<server_name>/<tool_name>` (eg- `github/git_commit`)
@@ -32,14 +63,114 @@ func validateServerName(name string) error {
 	return nil
 }
 
+// sanitizeServerName turns the name an MCP server reports about itself (eg- "GitHub MCP Server")
+// into a valid server name (eg- "github-mcp-server").
+// It returns an empty string if the name contains no usable characters.
+func sanitizeServerName(name string) string {
+	name = invalidServerNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-")
+}
+
+// toolNameFormat describes how the name of an MCP server and the name of one of its tools are combined
+// into the name the tool is exposed with, eg- `github/git_commit` or `github__git_commit`.
+type toolNameFormat struct {
+	separator string
+	// maxLength is the maximum length of the names tools are exposed with, 0 means unlimited.
+	// Longer names are truncated and end with a hash of the full name, so that they remain unique.
+	maxLength int
+}
+
+// toolNames is the format of the names tools are exposed with, by the MCP proxy server as well as the API
+var toolNames = toolNameFormat{separator: DefaultToolNameSeparator}
+
+// ConfigureToolNames sets the separator and the maximum length of the names tools are exposed with.
+// Some LLM providers only accept function names matching `^[a-zA-Z0-9_-]{1,64}$`, which is satisfied
+// by a separator like `__` and a maximum length of 64.
+// It must be called before the MCP service is created.
+func ConfigureToolNames(separator string, maxLength int) error {
+	if separator == "" || strings.ContainsAny(separator, " \t\r\n") {
+		return fmt.Errorf("invalid tool name separator '%s': it must not be empty or contain whitespace", separator)
+	}
+	if maxLength != 0 && maxLength < MinToolNameMaxLength {
+		return fmt.Errorf(
+			"invalid max tool name length %d: it must be 0 (unlimited) or at least %d", maxLength, MinToolNameMaxLength,
+		)
+	}
+	toolNames = toolNameFormat{separator: separator, maxLength: maxLength}
+	return nil
+}
+
+// validateServerNameForTools checks that the names of the server's tools can be split back into
+// the server name and the tool name.
+// The server name must not contain the separator and, when the length of tool names is limited,
+// it must leave enough room for the tool name.
+func validateServerNameForTools(name string) error {
+	if strings.Contains(name, toolNames.separator) {
+		return fmt.Errorf("invalid server name: '%s' must not contain the tool name separator '%s'", name, toolNames.separator)
+	}
+	if toolNames.maxLength > 0 {
+		// the server name and the separator are followed by at least one character of the tool name and the hash
+		maxServerNameLen := toolNames.maxLength - len(toolNames.separator) - shortenedToolNameHashLen - 2
+		if len(name) > maxServerNameLen {
+			return fmt.Errorf(
+				"invalid server name: '%s' is too long, tool names are limited to %d characters so it must not be longer than %d",
+				name, toolNames.maxLength, maxServerNameLen,
+			)
+		}
+	}
+	return nil
+}
+
+// validateToolAliasName checks that a tool can be exposed under the given alias.
+// Like a server name, the alias must not contain the tool name separator, otherwise
+// the exposed name could not be split back into the server name and the tool name.
+// It must also not contain whitespace or other characters that MCP clients reject in tool names.
+func validateToolAliasName(alias string) error {
+	if strings.Contains(alias, toolNames.separator) {
+		return fmt.Errorf("invalid alias: '%s' must not contain the tool name separator '%s'", alias, toolNames.separator)
+	}
+	if !validToolAlias.MatchString(alias) {
+		return fmt.Errorf(
+			"invalid alias: '%s' must only contain letters, numbers, hyphens, underscores and dots", alias,
+		)
+	}
+	return nil
+}
+
+// joinServerName combines the server name with the name of one of its prompts or loggers,
+// using the tool name separator.
+// Unlike tool names, the result is never shortened.
+func joinServerName(s, name string) string {
+	return s + toolNames.separator + name
+}
+
 // mergeServerToolNames combines the server name and tool name into a single tool name unique across the registry.
+// If the result is longer than the configured maximum length, it is truncated and ends with a hash of the full name.
 func mergeServerToolNames(s, t string) string {
-	return s + serverToolNameSep + t
+	name := joinServerName(s, t)
+	if !isShortenedToolName(name) || len(name) == toolNames.maxLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:shortenedToolNameHashLen]
+	n := toolNames.maxLength - shortenedToolNameHashLen - 1
+	// don't cut a multibyte character in half
+	for n > 0 && !utf8.RuneStart(name[n]) {
+		n--
+	}
+	return name[:n] + "-" + hash
+}
+
+// isShortenedToolName returns true if the name may have been shortened to fit the maximum length of tool names,
+// in which case it can't be split back into the server's tool name.
+func isShortenedToolName(name string) bool {
+	return toolNames.maxLength > 0 && len(name) >= toolNames.maxLength
 }
 
 // splitServerToolName splits the unique tool name into server name and tool name.
+// If the name was shortened, the tool name is the truncated one.
 func splitServerToolName(name string) (string, string, bool) {
-	serverName, toolName, ok := strings.Cut(name, serverToolNameSep)
+	serverName, toolName, ok := strings.Cut(name, toolNames.separator)
 	if !ok {
 		// there is no separator in tool name, we cannot extract mcp server name
 		// this is invalid input
@@ -48,6 +179,25 @@ func splitServerToolName(name string) (string, string, bool) {
 	return serverName, toolName, true
 }
 
+// mergeServerResourceURI combines the server name and the URI (or URI template) of one of its resources
+// into a single URI unique across the registry.
+func mergeServerResourceURI(s, uri string) string {
+	return proxyResourceURIPrefix + s + "/" + uri
+}
+
+// splitServerResourceURI splits a URI exposed by the MCP proxy server into server name and upstream resource URI.
+func splitServerResourceURI(uri string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(uri, proxyResourceURIPrefix)
+	if !ok {
+		return "", "", false
+	}
+	serverName, resourceURI, ok := strings.Cut(rest, "/")
+	if !ok || serverName == "" || resourceURI == "" {
+		return "", "", false
+	}
+	return serverName, resourceURI, true
+}
+
 // isLoopbackURL returns true if rawURL resolves to a loopback address.
 // It assumes that rawURL is a valid URL.
 func isLoopbackURL(rawURL string) bool {
@@ -70,41 +220,271 @@ func isLoopbackURL(rawURL string) bool {
 	return false
 }
 
-// createMcpServerConn creates a new MCP server connection and returns the client.
-func createMcpServerConn(ctx context.Context, s *model.McpServer) (*client.Client, error) {
-	var opts []transport.StreamableHTTPCOption
-	if s.BearerToken != "" {
-		// If bearer token is provided, set the Authorization header
-		o := transport.WithHTTPHeaders(map[string]string{
-			"Authorization": "Bearer " + s.BearerToken,
-		})
-		opts = append(opts, o)
+// validateServerTransport checks that the server contains all the settings required by its transport.
+// If no transport is specified, the server is expected to use one of the HTTP-based transports.
+func validateServerTransport(s *model.McpServer) error {
+	switch s.Transport {
+	case "", model.TransportStreamableHTTP, model.TransportSSE:
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid url: '%s' must be a valid http/https URL", s.URL)
+		}
+		if _, err := upstreamHeaders(s); err != nil {
+			return err
+		}
+		if _, err := upstreamTLSConfig(s); err != nil {
+			return err
+		}
+	case model.TransportStdio:
+		if s.Command == "" {
+			return fmt.Errorf("command is required for MCP servers using the %s transport", s.Transport)
+		}
+		if _, err := s.GetArgs(); err != nil {
+			return err
+		}
+		if _, err := s.GetEnv(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported transport: %s", s.Transport)
 	}
+	return nil
+}
 
-	c, err := client.NewStreamableHttpClient(s.URL, opts...)
+// validateServerCallPolicy checks the timeouts and retry settings of the server.
+// Zero values are valid and select the defaults.
+func validateServerCallPolicy(s *model.McpServer) error {
+	if s.ConnectTimeout < 0 {
+		return fmt.Errorf("invalid connect timeout: %d must not be negative", s.ConnectTimeout)
+	}
+	if s.CallTimeout < 0 {
+		return fmt.Errorf("invalid call timeout: %d must not be negative", s.CallTimeout)
+	}
+	if s.MaxRetries < 0 {
+		return fmt.Errorf("invalid max retries: %d must not be negative", s.MaxRetries)
+	}
+	return nil
+}
+
+// upstreamHeaders returns the HTTP headers to send in all requests to an HTTP-based MCP server.
+// It combines the custom headers of the server with the header required by its auth type.
+// Header names are canonicalized so that the auth header reliably overrides a custom header of the same name.
+func upstreamHeaders(s *model.McpServer) (map[string]string, error) {
+	custom, err := s.GetHeaders()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create streamable HTTP client for MCP server: %w", err)
+		return nil, err
+	}
+	headers := make(map[string]string, len(custom)+1)
+	for k, v := range custom {
+		if !validHeaderName.MatchString(k) {
+			return nil, fmt.Errorf("invalid header name: '%s'", k)
+		}
+		headers[http.CanonicalHeaderKey(k)] = v
+	}
+
+	authType := s.AuthType
+	if authType == "" && s.BearerToken != "" {
+		authType = model.AuthTypeBearer
+	}
+	switch authType {
+	case "":
+	case model.AuthTypeBearer:
+		if s.BearerToken == "" {
+			return nil, fmt.Errorf("bearer token is required for the %s auth type", authType)
+		}
+		headers["Authorization"] = "Bearer " + s.BearerToken
+	case model.AuthTypeBasic:
+		if s.BasicAuthUsername == "" {
+			return nil, fmt.Errorf("username is required for the %s auth type", authType)
+		}
+		creds := s.BasicAuthUsername + ":" + s.BasicAuthPassword
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(creds))
+	case model.AuthTypeHeader:
+		if !validHeaderName.MatchString(s.AuthHeaderName) {
+			return nil, fmt.Errorf("a valid auth header name is required for the %s auth type", authType)
+		}
+		if s.AuthHeaderValue == "" {
+			return nil, fmt.Errorf("auth header value is required for the %s auth type", authType)
+		}
+		headers[http.CanonicalHeaderKey(s.AuthHeaderName)] = s.AuthHeaderValue
+	case model.AuthTypeOAuth2:
+		// the access token is added to each request by the HTTP client, see newUpstreamHTTPClient()
+		u, err := url.Parse(s.OAuthTokenURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("a valid token url is required for the %s auth type", authType)
+		}
+		if s.OAuthClientID == "" {
+			return nil, fmt.Errorf("client id is required for the %s auth type", authType)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported auth type: %s", authType)
+	}
+	return headers, nil
+}
+
+// upstreamTLSConfig returns the TLS settings for connecting to an HTTP-based MCP server,
+// or nil if the server doesn't need any settings besides the defaults.
+func upstreamTLSConfig(s *model.McpServer) (*tls.Config, error) {
+	if s.TLSClientCert == "" && s.TLSClientKey == "" && s.TLSCACert == "" && s.TLSServerName == "" {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: s.TLSServerName,
+	}
+	if s.TLSClientCert != "" || s.TLSClientKey != "" {
+		if s.TLSClientCert == "" || s.TLSClientKey == "" {
+			return nil, fmt.Errorf("both the TLS client certificate and key are required for mutual TLS")
+		}
+		cert, err := tls.X509KeyPair([]byte(s.TLSClientCert), []byte(s.TLSClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS client certificate or key: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if s.TLSCACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(s.TLSCACert)) {
+			return nil, fmt.Errorf("invalid TLS CA bundle: no PEM-encoded certificates found")
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// newUpstreamHTTPClient returns the HTTP client used to send requests to an HTTP-based MCP server.
+func newUpstreamHTTPClient(s *model.McpServer) (*http.Client, error) {
+	tlsConfig, err := upstreamTLSConfig(s)
+	if err != nil {
+		return nil, err
+	}
+	var rt http.RoundTripper = http.DefaultTransport
+	if tlsConfig != nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = tlsConfig
+		rt = t
+	}
+	if s.AuthType == model.AuthTypeOAuth2 {
+		rt = &oauthTransport{base: rt, source: getOAuthTokenSource(s, rt)}
+	}
+	return &http.Client{Transport: rt}, nil
+}
+
+// createMcpServerConn creates a new MCP server connection and returns the client,
+// along with the information the server sent about itself during initialization.
+// It is only meant for HTTP-based transports.
+// Connections to stdio servers are owned by the stdio supervisor because each one is tied to a process.
+// If relay is not nil, the connection advertises the sampling and elicitation capabilities of the downstream client
+// and relays these requests of the server to it. Only streamable HTTP connections can receive requests from the server,
+// relay is ignored for SSE connections.
+// If listen is true, a streamable HTTP connection keeps a stream open on which the server can send notifications
+// outside of the response to any of our requests. SSE connections always listen, and so do those given a relay.
+func createMcpServerConn(
+	ctx context.Context, s *model.McpServer, relay *upstreamRequestRelay, listen bool,
+) (*client.Client, *mcp.InitializeResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.GetConnectTimeout())
+	defer cancel()
+
+	headers, err := upstreamHeaders(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	httpClient, err := newUpstreamHTTPClient(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var c *client.Client
+	switch s.Transport {
+	case model.TransportSSE:
+		t, err := transport.NewSSE(s.URL, transport.WithHeaders(headers), transport.WithHTTPClient(httpClient))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create SSE client for MCP server: %w", err)
+		}
+		c = client.NewClient(&cancellingTransport{t})
+		// The SSE stream carries all the responses of the connection, so it must outlive the
+		// context of the current request. It is closed along with the client.
+		// Closing the client also aborts the start if the server doesn't respond in time.
+		started := make(chan error, 1)
+		go func() {
+			started <- c.Start(context.Background())
+		}()
+		select {
+		case err = <-started:
+		case <-ctx.Done():
+			_ = c.Close()
+			err = fmt.Errorf("timed out after %s", s.GetConnectTimeout())
+		}
+		if err != nil {
+			return nil, nil, explainConnError(s, fmt.Errorf("failed to start SSE connection with MCP server: %w", err))
+		}
+	default:
+		opts := []transport.StreamableHTTPCOption{
+			transport.WithHTTPHeaders(headers), transport.WithHTTPBasicClient(httpClient),
+		}
+		var clientOpts []client.ClientOption
+		if relay != nil {
+			clientOpts = relay.clientOptions()
+		}
+		if listen || relay != nil {
+			// servers may send their requests and notifications outside of the response to any of our requests,
+			// on the stream the client keeps open for them
+			opts = append(opts, transport.WithContinuousListening())
+		}
+		t, err := transport.NewStreamableHTTP(s.URL, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create streamable HTTP client for MCP server: %w", err)
+		}
+		c = client.NewClient(&cancellingTransport{t}, clientOpts...)
+		// starting the client delivers the notifications and requests the server sends while responding to
+		// our requests (eg- progress) to the client's handlers.
+		// The stream the client may keep open for the server must outlive the context of the current request,
+		// it is closed along with the client.
+		if err := c.Start(context.Background()); err != nil {
+			return nil, nil, fmt.Errorf("failed to start streamable HTTP client for MCP server: %w", err)
+		}
+	}
+
+	info, err := initializeMcpClient(ctx, c, s)
+	if err != nil {
+		_ = c.Close()
+		return nil, nil, err
+	}
+	return c, info, nil
+}
+
+// initializeMcpClient performs the MCP initialization handshake with the upstream MCP server.
+func initializeMcpClient(ctx context.Context, c *client.Client, s *model.McpServer) (*mcp.InitializeResult, error) {
+	target := s.URL
+	if s.Transport == model.TransportStdio {
+		target = s.Name
 	}
 
 	initRequest := mcp.InitializeRequest{}
 	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
 	initRequest.Params.ClientInfo = mcp.Implementation{
-		Name:    "mcpjungle mcp client for " + s.URL,
+		Name:    "mcpjungle mcp client for " + target,
 		Version: "0.1",
 	}
+	// the client adds the sampling and elicitation capabilities itself if it has handlers for them
 	initRequest.Params.Capabilities = mcp.ClientCapabilities{}
 
-	_, err = c.Initialize(ctx, initRequest)
+	info, err := c.Initialize(ctx, initRequest)
 	if err != nil {
-		if errors.Is(err, syscall.ECONNREFUSED) && isLoopbackURL(s.URL) {
-			return nil, fmt.Errorf(
-				"connection to the MCP server %s was refused. "+
-					"If mcpjungle is running inside Docker, use 'host.docker.internal' as your MCP server's hostname",
-				s.URL,
-			)
-		}
-		return nil, fmt.Errorf("failed to initialize connection with MCP server: %w", err)
+		return nil, explainConnError(s, fmt.Errorf("failed to initialize connection with MCP server: %w", err))
 	}
+	return info, nil
+}
 
-	return c, nil
+// explainConnError adds a hint to errors caused by a refused connection to a loopback address,
+// which usually means that mcpjungle is running inside Docker.
+func explainConnError(s *model.McpServer, err error) error {
+	if errors.Is(err, syscall.ECONNREFUSED) && isLoopbackURL(s.URL) {
+		return fmt.Errorf(
+			"connection to the MCP server %s was refused. "+
+				"If mcpjungle is running inside Docker, use 'host.docker.internal' as your MCP server's hostname",
+			s.URL,
+		)
+	}
+	return err
 }
@@ -1,7 +1,22 @@
 package mcp
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidateServerName(t *testing.T) {
@@ -26,6 +41,34 @@ func TestValidateServerName(t *testing.T) {
 	}
 }
 
+func TestSanitizeServerName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"calculator", "calculator"},
+		{"GitHub MCP Server", "github-mcp-server"},
+		{"@modelcontextprotocol/server-filesystem", "modelcontextprotocol-server-filesystem"},
+		{"my_server v1.2", "my_server-v1-2"},
+		{"  spaced  ", "spaced"},
+		{"///", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := sanitizeServerName(tt.input)
+			if got != tt.want {
+				t.Errorf("sanitizeServerName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if got != "" {
+				if err := validateServerName(got); err != nil {
+					t.Errorf("sanitizeServerName(%q) returned an invalid name: %v", tt.input, err)
+				}
+			}
+		})
+	}
+}
+
 func TestMergeServerToolNames(t *testing.T) {
 	tests := []struct {
 		server string
@@ -67,6 +110,32 @@ func TestSplitServerToolName(t *testing.T) {
 	}
 }
 
+func TestSplitServerResourceURI(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantServer string
+		wantURI    string
+		wantOK     bool
+	}{
+		{mergeServerResourceURI("docs", "file:///README.md"), "docs", "file:///README.md", true},
+		{"mcpjungle://db/postgres://localhost/users?limit=1", "db", "postgres://localhost/users?limit=1", true},
+		{"mcpjungle://my_srv-2/test://x/y", "my_srv-2", "test://x/y", true},
+		{"mcpjungle://docs/", "", "", false},
+		{"mcpjungle:///file:///README.md", "", "", false},
+		{"mcpjungle://docs", "", "", false},
+		{"file:///README.md", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			server, uri, ok := splitServerResourceURI(tt.input)
+			if server != tt.wantServer || uri != tt.wantURI || ok != tt.wantOK {
+				t.Errorf("splitServerResourceURI(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.input, server, uri, ok, tt.wantServer, tt.wantURI, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestIsLoopbackURL(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -112,3 +181,295 @@ func TestIsLoopbackURL(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateServerTransport(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  model.McpServer
+		wantErr bool
+	}{
+		{"default transport", model.McpServer{URL: "http://localhost:8000/mcp"}, false},
+		{"https url", model.McpServer{Transport: model.TransportStreamableHTTP, URL: "https://example.com/mcp"}, false},
+		{"missing url", model.McpServer{Transport: model.TransportStreamableHTTP}, true},
+		{"sse", model.McpServer{Transport: model.TransportSSE, URL: "http://localhost:8000/sse"}, false},
+		{"non-http url", model.McpServer{URL: "ftp://example.com"}, true},
+		{"stdio", model.McpServer{Transport: model.TransportStdio, Command: "npx"}, false},
+		{"stdio with args", model.McpServer{Transport: model.TransportStdio, Command: "npx", Args: []byte(`["-y"]`)}, false},
+		{"stdio without command", model.McpServer{Transport: model.TransportStdio}, true},
+		{"stdio with invalid env", model.McpServer{Transport: model.TransportStdio, Command: "npx", Env: []byte(`["A=B"]`)}, true},
+		{"unknown transport", model.McpServer{Transport: "carrier_pigeon", URL: "http://localhost"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateServerTransport(&tt.server)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateServerTransport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpstreamHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  model.McpServer
+		want    map[string]string
+		wantErr bool
+	}{
+		{"no auth", model.McpServer{}, map[string]string{}, false},
+		{
+			"bearer token without auth type",
+			model.McpServer{BearerToken: "abc"},
+			map[string]string{"Authorization": "Bearer abc"},
+			false,
+		},
+		{"bearer without token", model.McpServer{AuthType: model.AuthTypeBearer}, nil, true},
+		{
+			"basic",
+			model.McpServer{AuthType: model.AuthTypeBasic, BasicAuthUsername: "user", BasicAuthPassword: "pass"},
+			map[string]string{"Authorization": "Basic dXNlcjpwYXNz"},
+			false,
+		},
+		{"basic without username", model.McpServer{AuthType: model.AuthTypeBasic}, nil, true},
+		{
+			"custom header",
+			model.McpServer{AuthType: model.AuthTypeHeader, AuthHeaderName: "x-api-key", AuthHeaderValue: "secret"},
+			map[string]string{"X-Api-Key": "secret"},
+			false,
+		},
+		{"custom header without name", model.McpServer{AuthType: model.AuthTypeHeader, AuthHeaderValue: "a"}, nil, true},
+		{
+			"custom header with invalid name",
+			model.McpServer{AuthType: model.AuthTypeHeader, AuthHeaderName: "x api key", AuthHeaderValue: "a"},
+			nil,
+			true,
+		},
+		{
+			"extra headers",
+			model.McpServer{Headers: []byte(`{"x-tenant": "acme", "X-Trace": "1"}`)},
+			map[string]string{"X-Tenant": "acme", "X-Trace": "1"},
+			false,
+		},
+		{
+			"auth overrides extra headers",
+			model.McpServer{BearerToken: "abc", Headers: []byte(`{"authorization": "Bearer xyz"}`)},
+			map[string]string{"Authorization": "Bearer abc"},
+			false,
+		},
+		{"invalid extra header name", model.McpServer{Headers: []byte(`{"x:y": "1"}`)}, nil, true},
+		{"invalid headers json", model.McpServer{Headers: []byte(`["x"]`)}, nil, true},
+		{
+			"oauth2 does not set a static header",
+			model.McpServer{AuthType: model.AuthTypeOAuth2, OAuthTokenURL: "https://auth.example.com/token", OAuthClientID: "id"},
+			map[string]string{},
+			false,
+		},
+		{"oauth2 without token url", model.McpServer{AuthType: model.AuthTypeOAuth2, OAuthClientID: "id"}, nil, true},
+		{
+			"oauth2 without client id",
+			model.McpServer{AuthType: model.AuthTypeOAuth2, OAuthTokenURL: "https://auth.example.com/token"},
+			nil,
+			true,
+		},
+		{"unknown auth type", model.McpServer{AuthType: "kerberos"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := upstreamHeaders(&tt.server)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("upstreamHeaders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("upstreamHeaders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestCert issues a certificate for localhost signed by parent (self-signed if parent is nil)
+// and returns it along with its PEM-encoded form and private key.
+func newTestCert(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool) (
+	*x509.Certificate, *ecdsa.PrivateKey, string, string,
+) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "mcpjungle-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		DNSNames:              []string{"internal.example.com"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return cert, key, string(certPEM), string(keyPEM)
+}
+
+func TestUpstreamTLSConfig(t *testing.T) {
+	_, _, certPEM, keyPEM := newTestCert(t, nil, nil, true)
+
+	tests := []struct {
+		name    string
+		server  model.McpServer
+		wantNil bool
+		wantErr bool
+	}{
+		{"no tls settings", model.McpServer{}, true, false},
+		{"client cert", model.McpServer{TLSClientCert: certPEM, TLSClientKey: keyPEM}, false, false},
+		{"client cert without key", model.McpServer{TLSClientCert: certPEM}, false, true},
+		{"client key without cert", model.McpServer{TLSClientKey: keyPEM}, false, true},
+		{"invalid client cert", model.McpServer{TLSClientCert: "garbage", TLSClientKey: keyPEM}, false, true},
+		{"ca bundle", model.McpServer{TLSCACert: certPEM}, false, false},
+		{"invalid ca bundle", model.McpServer{TLSCACert: "garbage"}, false, true},
+		{"server name", model.McpServer{TLSServerName: "internal.example.com"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := upstreamTLSConfig(&tt.server)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("upstreamTLSConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (got == nil) != tt.wantNil {
+				t.Errorf("upstreamTLSConfig() = %v, wantNil %v", got, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestNewUpstreamHTTPClientMutualTLS(t *testing.T) {
+	ca, caKey, caPEM, _ := newTestCert(t, nil, nil, true)
+	srvCert, srvKey, _, _ := newTestCert(t, ca, caKey, false)
+	_, _, clientCertPEM, clientKeyPEM := newTestCert(t, ca, caKey, false)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{srvCert.Raw}, PrivateKey: srvKey}},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		server  model.McpServer
+		wantErr bool
+	}{
+		{
+			"client cert and private ca",
+			model.McpServer{TLSClientCert: clientCertPEM, TLSClientKey: clientKeyPEM, TLSCACert: caPEM},
+			false,
+		},
+		{
+			"server name override",
+			model.McpServer{
+				TLSClientCert: clientCertPEM,
+				TLSClientKey:  clientKeyPEM,
+				TLSCACert:     caPEM,
+				TLSServerName: "internal.example.com",
+			},
+			false,
+		},
+		{"unknown ca", model.McpServer{TLSClientCert: clientCertPEM, TLSClientKey: clientKeyPEM}, true},
+		{"missing client cert", model.McpServer{TLSCACert: caPEM}, true},
+		{
+			"wrong server name",
+			model.McpServer{
+				TLSClientCert: clientCertPEM,
+				TLSClientKey:  clientKeyPEM,
+				TLSCACert:     caPEM,
+				TLSServerName: "other.example.com",
+			},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newUpstreamHTTPClient(&tt.server)
+			if err != nil {
+				t.Fatalf("newUpstreamHTTPClient() error = %v", err)
+			}
+			resp, err := c.Get(srv.URL)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfiguredToolNames(t *testing.T) {
+	defer func(f toolNameFormat) { toolNames = f }(toolNames)
+	if err := ConfigureToolNames("__", 32); err != nil {
+		t.Fatalf("ConfigureToolNames() error = %v", err)
+	}
+
+	if got := mergeServerToolNames("github", "git_commit"); got != "github__git_commit" {
+		t.Errorf("mergeServerToolNames() = %q, want %q", got, "github__git_commit")
+	}
+	server, tool, ok := splitServerToolName("github__git_commit")
+	if server != "github" || tool != "git_commit" || !ok {
+		t.Errorf("splitServerToolName() = (%q, %q, %v), want (%q, %q, true)", server, tool, ok, "github", "git_commit")
+	}
+
+	long := mergeServerToolNames("github", "list_pull_request_review_comments")
+	if len(long) != 32 || !strings.HasPrefix(long, "github__list_pull_reque-") {
+		t.Errorf("mergeServerToolNames() = %q, want a 32 characters long name starting with the full one", long)
+	}
+	if again := mergeServerToolNames("github", "list_pull_request_review_comments"); again != long {
+		t.Errorf("mergeServerToolNames() is not deterministic: %q != %q", again, long)
+	}
+	if other := mergeServerToolNames("github", "list_pull_request_review_threads"); other == long {
+		t.Errorf("mergeServerToolNames() gave the same shortened name %q to different tools", long)
+	}
+	if server, _, _ := splitServerToolName(long); server != "github" {
+		t.Errorf("splitServerToolName(%q) server = %q, want %q", long, server, "github")
+	}
+
+	if err := validateServerNameForTools("my__server"); err == nil {
+		t.Error("validateServerNameForTools() accepted a name containing the separator")
+	}
+	if err := validateServerNameForTools("a_very_long_server_name"); err == nil {
+		t.Error("validateServerNameForTools() accepted a name leaving no room for the tool name")
+	}
+
+	for _, tt := range []struct {
+		separator string
+		maxLength int
+	}{{"", 0}, {" ", 0}, {"__", 8}} {
+		if err := ConfigureToolNames(tt.separator, tt.maxLength); err == nil {
+			t.Errorf("ConfigureToolNames(%q, %d) error = nil, want an error", tt.separator, tt.maxLength)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// toolSyncDelay is how long a sync waits after a list_changed notification,
+	// so that a burst of notifications from the same server results in a single sync.
+	toolSyncDelay = time.Second
+	// toolSyncTimeout bounds the time a sync triggered by a notification may take.
+	toolSyncTimeout = time.Minute
+	// watchBackoffMin is the delay before reconnecting a watch connection that was lost.
+	watchBackoffMin = time.Second
+	// watchBackoffMax caps the delay between consecutive attempts to reconnect a watch connection.
+	// A connection must stay up for longer than this before the delay goes back to watchBackoffMin,
+	// so that a server which keeps dropping the connection right away isn't reconnected to (and synced) in a loop.
+	watchBackoffMax = time.Minute
+	// watchPingInterval is how often a watch connection is pinged to find out whether it is still alive.
+	watchPingInterval = 30 * time.Second
+)
+
+// errListChangedUnsupported is returned when an upstream MCP server does not notify its clients
+// about changes to its tools, resources or prompts, so there is no point in watching it.
+var errListChangedUnsupported = errors.New("MCP server does not send list_changed notifications")
+
+// toolWatcherSet keeps a notification-listening connection to every HTTP-based upstream MCP server
+// that announces the listChanged capability for its tools, resources or prompts, and resyncs the server
+// whenever it notifies that any of them changed.
+// stdio servers don't need a separate connection, the supervisor forwards their notifications instead.
+type toolWatcherSet struct {
+	mu       sync.Mutex
+	watchers map[string]context.CancelFunc
+	// pending holds the servers for which a sync is scheduled but hasn't started yet
+	pending map[string]bool
+
+	// sync brings the tools of the given server in the registry up to date
+	sync func(ctx context.Context, name string) error
+
+	// backoffMin and backoffMax bound the delay before reconnecting a lost watch connection
+	backoffMin, backoffMax time.Duration
+	// pingInterval is how often a watch connection is pinged
+	pingInterval time.Duration
+}
+
+func newToolWatcherSet(sync func(ctx context.Context, name string) error) *toolWatcherSet {
+	return &toolWatcherSet{
+		watchers:     make(map[string]context.CancelFunc),
+		pending:      make(map[string]bool),
+		sync:         sync,
+		backoffMin:   watchBackoffMin,
+		backoffMax:   watchBackoffMax,
+		pingInterval: watchPingInterval,
+	}
+}
+
+// start begins watching the given HTTP-based server in the background, replacing any existing watcher.
+func (ws *toolWatcherSet) start(s *model.McpServer) {
+	if s.Transport == model.TransportStdio {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ws.mu.Lock()
+	if stop, ok := ws.watchers[s.Name]; ok {
+		stop()
+	}
+	ws.watchers[s.Name] = cancel
+	ws.mu.Unlock()
+
+	w := *s
+	go ws.watch(ctx, &w)
+}
+
+// stop closes the watch connection of the given server.
+// It is a no-op if the server is not being watched.
+func (ws *toolWatcherSet) stop(name string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if stop, ok := ws.watchers[name]; ok {
+		stop()
+		delete(ws.watchers, name)
+	}
+}
+
+// stopAll closes all watch connections.
+func (ws *toolWatcherSet) stopAll() {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	for name, stop := range ws.watchers {
+		stop()
+		delete(ws.watchers, name)
+	}
+}
+
+// handleNotification schedules a sync of the given server if the notification says its tools, resources
+// or prompts changed.
+func (ws *toolWatcherSet) handleNotification(name string, n mcp.JSONRPCNotification) {
+	switch n.Method {
+	case mcp.MethodNotificationToolsListChanged,
+		mcp.MethodNotificationResourcesListChanged,
+		mcp.MethodNotificationPromptsListChanged:
+		ws.scheduleSync(name)
+	}
+}
+
+// scheduleSync syncs the tools of the given server shortly, unless a sync is already scheduled.
+// It never blocks, because it is called from the goroutines reading from upstream connections.
+func (ws *toolWatcherSet) scheduleSync(name string) {
+	ws.mu.Lock()
+	if ws.pending[name] {
+		ws.mu.Unlock()
+		return
+	}
+	ws.pending[name] = true
+	ws.mu.Unlock()
+
+	go func() {
+		time.Sleep(toolSyncDelay)
+		ws.mu.Lock()
+		delete(ws.pending, name)
+		ws.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), toolSyncTimeout)
+		defer cancel()
+		if err := ws.sync(ctx, name); err != nil {
+			log.Printf("[watch] failed to sync MCP server %s after it notified a change: %v", name, err)
+		}
+	}()
+}
+
+// watch keeps a watch connection to the server open until ctx is cancelled, reconnecting with backoff.
+// Notifications may have been missed while the connection was down, so the tools are synced after every reconnect.
+// The backoff is only reset once a connection has stayed up for longer than backoffMax,
+// which also bounds how often a server whose connection keeps dropping is synced.
+func (ws *toolWatcherSet) watch(ctx context.Context, s *model.McpServer) {
+	backoff := ws.backoffMin
+	for connected := false; ; {
+		var connectedAt time.Time
+		err := ws.watchOnce(ctx, s, func() {
+			if connected {
+				ws.scheduleSync(s.Name)
+			}
+			connected = true
+			connectedAt = time.Now()
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, errListChangedUnsupported) {
+			return
+		}
+		if !connectedAt.IsZero() && time.Since(connectedAt) > ws.backoffMax {
+			backoff = ws.backoffMin
+		}
+		log.Printf("[watch] lost watch connection to MCP server %s, reconnecting in %s: %v", s.Name, backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff = min(backoff*2, ws.backoffMax)
+	}
+}
+
+// watchOnce opens a watch connection to the server and blocks until it is lost or ctx is cancelled.
+// onConnected is called once the connection is ready to receive notifications.
+// The client keeps the stream on which the server sends its notifications open, and reopens it if it drops,
+// so the connection is only considered lost once the server stops answering pings (eg- its session expired).
+func (ws *toolWatcherSet) watchOnce(ctx context.Context, s *model.McpServer, onConnected func()) error {
+	c, _, err := createMcpServerConn(ctx, s, nil, true)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	caps := c.GetServerCapabilities()
+	toolsChange := caps.Tools != nil && caps.Tools.ListChanged
+	resourcesChange := caps.Resources != nil && caps.Resources.ListChanged
+	promptsChange := caps.Prompts != nil && caps.Prompts.ListChanged
+	if !toolsChange && !resourcesChange && !promptsChange {
+		return errListChangedUnsupported
+	}
+	c.OnNotification(func(n mcp.JSONRPCNotification) {
+		ws.handleNotification(s.Name, n)
+	})
+	onConnected()
+	return pingUntilLost(ctx, c, ws.pingInterval)
+}
+
+// pingUntilLost pings the server periodically and returns when a ping fails or ctx is cancelled.
+func pingUntilLost(ctx context.Context, c *client.Client, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		pingCtx, cancel := context.WithTimeout(ctx, poolHealthCheckTimeout)
+		err := c.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+}
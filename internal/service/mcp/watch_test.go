@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFlakyStreamableServer starts a streamable HTTP MCP server that announces tools/list_changed notifications
+// but forgets every session right after initializing it, so that pings fail.
+// It returns the number of sessions that were initialized.
+func newFlakyStreamableServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var sessions atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodDelete:
+			return
+		}
+		var req struct {
+			ID     any    `json:"id"`
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		if req.Method != "initialize" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		sessions.Add(1)
+		result := map[string]any{
+			"protocolVersion": "2025-03-26",
+			"capabilities":    map[string]any{"tools": map[string]any{"listChanged": true}},
+			"serverInfo":      map[string]any{"name": "flaky", "version": "1.0.0"},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Mcp-Session-Id", "session")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &sessions
+}
+
+func TestWatchBacksOffWhenConnectionIsLostImmediately(t *testing.T) {
+	srv, sessions := newFlakyStreamableServer(t)
+
+	ws := newToolWatcherSet(func(ctx context.Context, name string) error { return nil })
+	ws.backoffMin = 10 * time.Millisecond
+	ws.backoffMax = 80 * time.Millisecond
+	ws.pingInterval = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+	ws.watch(ctx, &model.McpServer{Name: "flaky", Transport: model.TransportStreamableHTTP, URL: srv.URL})
+
+	// reconnecting after 10, 20, 40, 80, 80, ... ms leaves room for about 7 connections,
+	// resetting the backoff on every reconnect would make dozens
+	if n := sessions.Load(); n < 3 || n > 10 {
+		t.Errorf("connected %d times, want between 3 and 10", n)
+	}
+}
+
+func TestWatchSyncsOnListChanged(t *testing.T) {
+	upstream := server.NewMCPServer("upstream", "0.0.0", server.WithToolCapabilities(true))
+	srv := server.NewTestStreamableHTTPServer(upstream)
+	t.Cleanup(srv.Close)
+
+	synced := make(chan string, 1)
+	ws := newToolWatcherSet(func(ctx context.Context, name string) error {
+		select {
+		case synced <- name:
+		default:
+		}
+		return nil
+	})
+	ws.start(&model.McpServer{Name: "upstream", Transport: model.TransportStreamableHTTP, URL: srv.URL + "/mcp"})
+	t.Cleanup(ws.stopAll)
+
+	// the notification is only received once the client opened its stream, so keep changing the tools until it is
+	timeout := time.After(10 * time.Second)
+	for i := 0; ; i++ {
+		upstream.AddTool(mcp.NewTool(string(rune('a'+i%26))), nil)
+		select {
+		case name := <-synced:
+			if name != "upstream" {
+				t.Errorf("synced MCP server %s, want upstream", name)
+			}
+			return
+		case <-timeout:
+			t.Fatal("the MCP server was never synced after notifying that its tools changed")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
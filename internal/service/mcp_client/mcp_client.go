@@ -1,6 +1,7 @@
 package mcp_client
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/mcpjungle/mcpjungle/internal"
@@ -53,6 +54,33 @@ func (m *McpClientService) GetClientByToken(token string) (*model.McpClient, err
 	return &client, nil
 }
 
+// UpdateClient changes the description and the allow list of an MCP client.
+// A nil value leaves the corresponding setting unchanged.
+// The new allow list applies to the client's next requests, including those of its open sessions.
+func (m *McpClientService) UpdateClient(name string, description *string, allowList []string) (*model.McpClient, error) {
+	var client model.McpClient
+	if err := m.db.Where("name = ?", name).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("client %s not found", name)
+		}
+		return nil, err
+	}
+	if description != nil {
+		client.Description = *description
+	}
+	if allowList != nil {
+		list, err := json.Marshal(allowList)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize allow list: %w", err)
+		}
+		client.AllowList = list
+	}
+	if err := m.db.Save(&client).Error; err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
 // DeleteClient removes an MCP client from the database and immediately revokes its access.
 // It is an idempotent operation. Deleting a client that does not exist will not return an error.
 func (m *McpClientService) DeleteClient(name string) error {
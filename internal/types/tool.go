@@ -6,4 +6,28 @@ type ToolInvokeResult struct {
 	Meta    map[string]any   `json:"_meta,omitempty"`
 	IsError bool             `json:"isError,omitempty"`
 	Content []map[string]any `json:"content"`
+	// StructuredContent is the result of the tool as a JSON object, if the tool provides one
+	StructuredContent map[string]any `json:"structuredContent,omitempty"`
+	// Warning is set when the result doesn't conform to the tool's output schema.
+	// The result is returned as the tool provided it regardless.
+	Warning string `json:"warning,omitempty"`
+}
+
+// ToolSyncResult describes how the tools of an MCP server in the registry changed after
+// re-fetching them from the server. All tool names are canonical (ie- prefixed with the server name).
+type ToolSyncResult struct {
+	Added   []string `json:"added"`
+	Updated []string `json:"updated"`
+	Removed []string `json:"removed"`
+}
+
+// ToolOverridesUpdate describes the changes to make to the overrides of a tool.
+// Nil fields are left untouched and empty values remove the override.
+type ToolOverridesUpdate struct {
+	Alias       *string `json:"alias"`
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	// ParameterDescriptions contains the new descriptions of some of the tool's input parameters, by parameter name.
+	// The overrides of the other parameters are left untouched.
+	ParameterDescriptions map[string]string `json:"parameter_descriptions"`
 }